@@ -0,0 +1,278 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// loadReport 讀取以 -format json 輸出的結果
+func loadReport(path string) (*report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var r report
+	if err := json.NewDecoder(f).Decode(&r); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	return &r, nil
+}
+
+// compareKey 以檔名（不含目錄）、實作、參數與 goroutine 數對應兩份結果
+type compareKey struct {
+	file    string
+	impl    string
+	params  string
+	threads int
+}
+
+func keysOf(r *report) (map[compareKey]*implReport, []compareKey) {
+	m := make(map[compareKey]*implReport)
+	var order []compareKey
+	for _, fr := range r.Files {
+		for _, ir := range fr.Impls {
+			k := compareKey{filepath.Base(fr.File), ir.Impl, formatParams(ir.Params), ir.Threads}
+			if _, dup := m[k]; !dup {
+				order = append(order, k)
+			}
+			m[k] = ir
+		}
+	}
+	return m, order
+}
+
+func runTimes(ir *implReport) []float64 {
+	ms := make([]float64, len(ir.Runs))
+	for i, run := range ir.Runs {
+		ms[i] = run.Ms
+	}
+	return ms
+}
+
+func median(values []float64) float64 {
+	s := slices.Clone(values)
+	slices.Sort(s)
+	n := len(s)
+	if n == 0 {
+		return math.NaN()
+	}
+	if n%2 == 1 {
+		return s[n/2]
+	}
+	return (s[n/2-1] + s[n/2]) / 2
+}
+
+// compareReports 以每次執行的耗時比較 base 與 cur 中相同的組合，
+// 中位數變慢超過 threshold 且 Mann-Whitney U 檢定的 p 值小於 alpha 時視為退步。
+// 結果表寫到 w，回傳是否有任何退步
+func compareReports(w io.Writer, base, cur *report, threshold, alpha float64) bool {
+	baseImpls, _ := keysOf(base)
+	curImpls, order := keysOf(cur)
+
+	regressed := false
+	underpowered := 0 // 執行次數太少、不可能達到 p < alpha 的組合數
+	rows := make([][]string, 0, len(order))
+	for _, k := range order {
+		old, ok := baseImpls[k]
+		if !ok {
+			continue
+		}
+		x, y := runTimes(old), runTimes(curImpls[k])
+		oldMed, newMed := median(x), median(y)
+		delta := (newMed - oldMed) / oldMed
+		p := mannWhitneyU(x, y)
+
+		verdict := "~"
+		switch {
+		case minMannWhitneyP(len(x), len(y)) >= alpha:
+			verdict = "too few runs"
+			underpowered++
+		case p >= alpha || math.Abs(delta) <= threshold:
+		case delta > 0:
+			verdict = "REGRESSION"
+			regressed = true
+		default:
+			verdict = "improved"
+		}
+		params := k.params
+		if params == "" {
+			params = "-"
+		}
+		rows = append(rows, []string{
+			k.file,
+			k.impl,
+			params,
+			fmt.Sprintf("%d", k.threads),
+			fmt.Sprintf("%.3f", oldMed),
+			fmt.Sprintf("%.3f", newMed),
+			fmt.Sprintf("%+.2f%%", delta*100),
+			fmt.Sprintf("%.3f (n=%d+%d)", p, len(x), len(y)),
+			verdict,
+		})
+	}
+
+	fmt.Fprintf(w, "BASELINE COMPARISON (median ms, threshold %.1f%%, alpha %.3f)\n", threshold*100, alpha)
+	table := newTable(w, []string{"File", "Impl", "Params", "Threads", "Old(ms)", "New(ms)", "Delta", "P", "Verdict"})
+	table.AppendBulk(rows)
+	table.Render()
+	if len(rows) == 0 {
+		fmt.Fprintln(w, "no matching file/impl pairs between the baseline and this run")
+	}
+	if underpowered > 0 {
+		fmt.Fprintf(w, "WARNING: %d of %d pairs have too few runs for any p value below alpha %.3f, so no regression can be detected; "+
+			"use at least %d runs on both sides (-runs %d)\n", underpowered, len(rows), alpha, runsNeeded(alpha), runsNeeded(alpha))
+	}
+	return regressed
+}
+
+// minMannWhitneyP 回傳樣本數為 n1、n2 時 mannWhitneyU 可能的最小 p 值（兩組完全分開、沒有同分）
+func minMannWhitneyP(n1, n2 int) float64 {
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+	if n1 > 50 || n2 > 50 {
+		return 0 // 常態近似下足以小於任何常用的 alpha
+	}
+	return math.Min(1, 2*exactUCDF(n1, n2, 0))
+}
+
+// runsNeeded 回傳兩邊執行次數相同時，可能得到 p < alpha 的最少執行次數
+func runsNeeded(alpha float64) int {
+	n := 1
+	for n < 50 && minMannWhitneyP(n, n) >= alpha {
+		n++
+	}
+	return n
+}
+
+// runCompare 實作 benchrun compare [flags] old.json new.json
+func runCompare(args []string) {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	threshold := fs.Float64("threshold", 0.05, "relative slowdown of the median treated as a regression")
+	alpha := fs.Float64("alpha", 0.05, "significance level of the Mann-Whitney U test")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: benchrun compare [-threshold r] [-alpha a] old.json new.json")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	base, err := loadReport(fs.Arg(0))
+	if err != nil {
+		log.Fatalf("load baseline: %v", err)
+	}
+	cur, err := loadReport(fs.Arg(1))
+	if err != nil {
+		log.Fatalf("load results: %v", err)
+	}
+	if compareReports(os.Stdout, base, cur, *threshold, *alpha) {
+		os.Exit(1)
+	}
+}
+
+// mannWhitneyU 回傳 x 與 y 來自相同分布的雙尾 p 值。
+// 沒有同分且樣本不大時使用精確分布，否則使用含同分修正的常態近似
+func mannWhitneyU(x, y []float64) float64 {
+	n1, n2 := len(x), len(y)
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	type sample struct {
+		v     float64
+		fromX bool
+	}
+	all := make([]sample, 0, n1+n2)
+	for _, v := range x {
+		all = append(all, sample{v, true})
+	}
+	for _, v := range y {
+		all = append(all, sample{v, false})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].v < all[j].v })
+
+	// 同分者取平均名次
+	rankX := 0.0
+	tieTerm := 0.0
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].v == all[i].v {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for _, s := range all[i:j] {
+			if s.fromX {
+				rankX += rank
+			}
+		}
+		t := float64(j - i)
+		tieTerm += t*t*t - t
+		i = j
+	}
+
+	u1 := rankX - float64(n1*(n1+1))/2
+	u := math.Min(u1, float64(n1*n2)-u1)
+
+	if tieTerm == 0 && n1 <= 50 && n2 <= 50 {
+		return math.Min(1, 2*exactUCDF(n1, n2, int(u)))
+	}
+
+	n := float64(n1 + n2)
+	mu := float64(n1*n2) / 2
+	sigma := math.Sqrt(float64(n1*n2) / 12 * ((n + 1) - tieTerm/(n*(n-1))))
+	if sigma == 0 {
+		return 1
+	}
+	z := (math.Abs(u1-mu) - 0.5) / sigma
+	if z < 0 {
+		z = 0
+	}
+	return math.Erfc(z / math.Sqrt2)
+}
+
+// exactUCDF 回傳樣本數為 n1、n2 且沒有同分時 U <= u 的機率
+func exactUCDF(n1, n2, u int) float64 {
+	// counts[m][n][k] 為 m 個 x 與 n 個 y 的排列中 U = k 的個數，
+	// 依最大元素屬於 x（貢獻 n）或 y 遞推，只保留前一個 m 以節省記憶體
+	prev := make([][]float64, n2+1)
+	for n := range prev {
+		prev[n] = []float64{1} // m = 0 時 U 只能為 0
+	}
+	for m := 1; m <= n1; m++ {
+		cur := make([][]float64, n2+1)
+		cur[0] = []float64{1}
+		for n := 1; n <= n2; n++ {
+			c := make([]float64, m*n+1)
+			for k, v := range prev[n] {
+				c[k+n] += v
+			}
+			for k, v := range cur[n-1] {
+				c[k] += v
+			}
+			cur[n] = c
+		}
+		prev = cur
+	}
+
+	dist := prev[n2]
+	total, below := 0.0, 0.0
+	for k, v := range dist {
+		total += v
+		if k <= u {
+			below += v
+		}
+	}
+	return below / total
+}
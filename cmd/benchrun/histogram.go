@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"time"
+
+	"github.com/Hakuto4838/SkipList.git/datastream"
+)
+
+// subBucketBits 每個 2 的冪次區間再細分為 2^subBucketBits 格，相對誤差約 3%
+const subBucketBits = 5
+
+// latencyHist 是 HDR 風格的對數-線性直方圖，以固定大小的陣列記錄奈秒延遲，
+// Record 只做位元運算與一次加法，不配置記憶體
+type latencyHist struct {
+	counts [64 << subBucketBits]uint64
+	total  uint64
+	max    int64
+}
+
+// bucketOf 回傳 v 所在的格子，小於 2^subBucketBits 的值各自一格
+func bucketOf(v uint64) int {
+	if v < 1<<subBucketBits {
+		return int(v)
+	}
+	shift := bits.Len64(v) - 1 - subBucketBits
+	sub := (v >> shift) & (1<<subBucketBits - 1)
+	return (shift+1)<<subBucketBits + int(sub)
+}
+
+// bucketHigh 回傳格子 idx 所能代表的最大值
+func bucketHigh(idx int) int64 {
+	if idx < 1<<subBucketBits {
+		return int64(idx)
+	}
+	shift := idx>>subBucketBits - 1
+	sub := int64(idx & (1<<subBucketBits - 1))
+	return (1<<subBucketBits+sub+1)<<shift - 1
+}
+
+func (h *latencyHist) Record(d time.Duration) {
+	v := int64(d)
+	if v < 0 {
+		v = 0
+	}
+	h.counts[bucketOf(uint64(v))]++
+	h.total++
+	if v > h.max {
+		h.max = v
+	}
+}
+
+// Merge 將 o 的紀錄加入 h
+func (h *latencyHist) Merge(o *latencyHist) {
+	for i, c := range o.counts {
+		h.counts[i] += c
+	}
+	h.total += o.total
+	if o.max > h.max {
+		h.max = o.max
+	}
+}
+
+func (h *latencyHist) Count() uint64 {
+	return h.total
+}
+
+func (h *latencyHist) Max() time.Duration {
+	return time.Duration(h.max)
+}
+
+// Percentile 回傳第 q 百分位（0 < q <= 100）所在格子的上界，不超過實際最大值
+func (h *latencyHist) Percentile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	target := uint64(math.Ceil(q / 100 * float64(h.total)))
+	if target == 0 {
+		target = 1
+	}
+	acc := uint64(0)
+	for i, c := range h.counts {
+		acc += c
+		if acc >= target {
+			if high := bucketHigh(i); high < h.max {
+				return time.Duration(high)
+			}
+			return time.Duration(h.max)
+		}
+	}
+	return time.Duration(h.max)
+}
+
+// percentiles 為結果表列出的百分位
+var percentiles = []float64{50, 90, 99, 99.9}
+
+// opLatency 依操作種類分別記錄延遲
+type opLatency [datastream.OpDelete + 1]latencyHist
+
+func (l *opLatency) Merge(o *opLatency) {
+	for i := range l {
+		l[i].Merge(&o[i])
+	}
+}
+
+// all 回傳合併所有操作種類的直方圖
+func (l *opLatency) all() *latencyHist {
+	var h latencyHist
+	for i := range l {
+		h.Merge(&l[i])
+	}
+	return &h
+}
+
+// latencyHeader 回傳延遲欄位的標題
+func latencyHeader() []string {
+	header := make([]string, 0, len(percentiles)+1)
+	for _, q := range percentiles {
+		header = append(header, fmt.Sprintf("P%g(ns)", q))
+	}
+	return append(header, "Max(ns)")
+}
+
+// latencyCells 回傳 h 對應 latencyHeader 的欄位值
+func latencyCells(h *latencyHist) []string {
+	cells := make([]string, 0, len(percentiles)+1)
+	for _, q := range percentiles {
+		cells = append(cells, fmt.Sprintf("%d", h.Percentile(q).Nanoseconds()))
+	}
+	return append(cells, fmt.Sprintf("%d", h.Max().Nanoseconds()))
+}
+
+// renderLatencyTable 依操作種類列出各實作的延遲分布，沒有該種操作的列略過
+func renderLatencyTable(o *output, toRun []string, lats map[string]*opLatency) {
+	rows := make([][]string, 0, len(toRun)*len(opLatency{}))
+	for _, impl := range toRun {
+		lat, ok := lats[impl]
+		if !ok {
+			continue
+		}
+		for i := range lat {
+			h := &lat[i]
+			if h.Count() == 0 {
+				continue
+			}
+			row := []string{impl, datastream.OperationType(i).String(), fmt.Sprintf("%d", h.Count())}
+			rows = append(rows, append(row, latencyCells(h)...))
+		}
+	}
+
+	table := o.newTable(append([]string{"Impl", "Op", "Count"}, latencyHeader()...))
+	table.AppendBulk(rows)
+	table.Render()
+}
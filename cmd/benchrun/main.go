@@ -7,6 +7,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -14,17 +15,36 @@ import (
 	"github.com/Hakuto4838/SkipList.git/datastream"
 	"github.com/Hakuto4838/SkipList.git/skiplist"
 	"github.com/Hakuto4838/SkipList.git/skiplist/analyTool"
-	"github.com/Hakuto4838/SkipList.git/skiplist/basic"
-	"github.com/Hakuto4838/SkipList.git/skiplist/la"
-	"github.com/Hakuto4838/SkipList.git/skiplist/splay"
-	"github.com/olekukonko/tablewriter"
+	"github.com/Hakuto4838/SkipList.git/skiplist/registry"
+
+	// 以空白匯入登錄 -impl 可選的實作
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/Tlist"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/basic"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/falldown"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/gravity"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/la"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/lockfree"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/rebuildsl"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/sharded"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/splay"
 )
 
 type laPutWithNP interface {
-	PutWithNP(key skiplist.K, value skiplist.V, np float64)
+	PutWithNP(key skiplist.K, value skiplist.V, np float64) (skiplist.V, bool)
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "compare":
+			runCompare(os.Args[2:])
+			return
+		case "verify":
+			runVerify(os.Args[2:])
+			return
+		}
+	}
+
 	// Input: either provide -file, -dir, or provide -out and generation params
 	var file string
 	var dir string
@@ -37,8 +57,13 @@ func main() {
 
 	var impls string
 	var runs int
-	var splayP float64
-	var rebuildP float64
+	var threads string
+	var format string
+	var outPath string
+	var baseline string
+	var threshold float64
+	var alpha float64
+	var latency bool
 	var phase1Ratio float64
 	var deleteRatio float64
 
@@ -53,12 +78,33 @@ func main() {
 	flag.Float64Var(&phase1Ratio, "phase1Ratio", 0.5, "ratio of phase1 operations")
 	flag.Float64Var(&deleteRatio, "deleteRatio", 0.1, "ratio of delete operations")
 
-	flag.StringVar(&impls, "impl", "all", "implementations to run: all or comma list (basic,splay,la,rebuild,gravity,falldown)")
+	flag.StringVar(&impls, "impl", "all", "implementations to run: all or comma list ("+strings.Join(registry.Names(), ",")+")")
 	flag.IntVar(&runs, "runs", 5, "how many times to repeat each benchmark")
-	flag.Float64Var(&splayP, "splay.p", 0.01, "probability for splay updates")
-	flag.Float64Var(&rebuildP, "rebuild.p", 0.1, "probability for rebuild balancing")
+	flag.BoolVar(&latency, "latency", false, "after each timed run, replay once more on a fresh structure timing every op and report latency percentiles (kept out of Avg(ms) and Ops/s)")
+	flag.StringVar(&threads, "threads", "1", "goroutines replaying the ops concurrently: N (scales 1,2,4,...,N) or comma list; >1 wraps non-thread-safe impls with syncsl")
+	cfg := registry.Config{}
+	flag.Var(cfg, "param", "implementation parameter impl.key=value, may be repeated (parameters and defaults are listed below)")
+	// 舊版的參數旗標，保留給既有的腳本使用
+	for _, name := range []string{"splay", "rebuild"} {
+		flag.Func(name+".p", "deprecated: same as -param "+name+".p=value", func(s string) error {
+			log.Printf("-%s.p is deprecated, use -param %s.p=%s", name, name, s)
+			return cfg.Set(name + ".p=" + s)
+		})
+	}
+	flag.StringVar(&format, "format", "table", "result format: table, json or csv (json/csv include every run; progress goes to stderr)")
+	flag.StringVar(&outPath, "o", "", "write results to this file instead of stdout")
+	flag.StringVar(&baseline, "baseline", "", "compare against a result saved with -format json; exits 1 on regression")
+	flag.Float64Var(&threshold, "threshold", 0.05, "relative slowdown of the median treated as a regression (with -baseline)")
+	flag.Float64Var(&alpha, "alpha", 0.05, "significance level of the Mann-Whitney U test (with -baseline)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags]\n       %s compare [flags] old.json new.json\n       %s verify file.bin...\n", os.Args[0], os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+		registry.PrintUsage(flag.CommandLine.Output())
+	}
 	flag.Parse()
 
+	o, closeOut := newOutput(format, outPath, seed)
+
 	var benchPaths []string
 
 	// 判斷模式: -dir 優先於 -file
@@ -72,10 +118,10 @@ func main() {
 			log.Fatalf("no .bin files found in directory: %s", dir)
 		}
 		benchPaths = files
-		fmt.Printf("Found %d bench files in directory: %s\n", len(benchPaths), dir)
+		fmt.Fprintf(o.progress, "Found %d bench files in directory: %s\n", len(benchPaths), dir)
 	} else if file != "" {
 		benchPaths = []string{file}
-		fmt.Printf("bench_file: %s\n", file)
+		fmt.Fprintf(o.progress, "bench_file: %s\n", file)
 	} else {
 		// validate generation inputs
 		if out == "" {
@@ -84,23 +130,59 @@ func main() {
 		if n <= 0 || k < 0 {
 			log.Fatalf("invalid -n or -k: n=%d k=%d", n, k)
 		}
-		fmt.Printf("generated bench_file: %s\n", out)
+		fmt.Fprintf(o.progress, "generated bench_file: %s\n", out)
 		if _, err := datastream.WriteBenchFileFromZipfV2(n, a, b, uint64(seed), k, phase1Ratio, deleteRatio, out, false); err != nil {
 			log.Fatalf("generate bench file: %v", err)
 		}
 		benchPaths = []string{out}
 	}
 
-	toRun := parseImpls(impls)
-	fmt.Printf("implementations to test: %s\n", strings.Join(toRun, ","))
-	fmt.Println(strings.Repeat("=", 80))
-
-	// 如果是多個檔案，匯總統計
-	if len(benchPaths) > 1 {
-		runBatchBenchmark(benchPaths, toRun, runs, seed, splayP, rebuildP)
+	toRun, err := registry.ParseNames(impls)
+	if err != nil {
+		log.Fatalf("invalid -impl: %v", err)
+	}
+	// 先各建立一次以檢查參數，避免跑到一半才失敗
+	for _, impl := range toRun {
+		if _, err := cfg.New(impl, registry.Env{Seed: seed}); err != nil {
+			log.Fatalf("invalid -param: %v", err)
+		}
+	}
+	fmt.Fprintf(o.progress, "implementations to test: %s\n", strings.Join(toRun, ","))
+	fmt.Fprintln(o.progress, strings.Repeat("=", 80))
+
+	if threadCounts := parseThreads(threads); !slices.Equal(threadCounts, []int{1}) {
+		// 多個 goroutine 時改為擴展測試
+		runScalingBenchmark(o, benchPaths, toRun, threadCounts, runs, seed, cfg)
+	} else if len(benchPaths) > 1 {
+		// 如果是多個檔案，匯總統計
+		runBatchBenchmark(o, benchPaths, toRun, runs, seed, cfg, latency)
 	} else {
 		// 單一檔案，顯示詳細結果
-		runBenchmark(benchPaths[0], toRun, runs, seed, splayP, rebuildP)
+		runBenchmark(o, benchPaths[0], toRun, runs, seed, cfg, latency)
+	}
+
+	if err := o.flush(); err != nil {
+		log.Fatalf("write results: %v", err)
+	}
+
+	regressed := false
+	if baseline != "" {
+		base, err := loadReport(baseline)
+		if err != nil {
+			log.Fatalf("load baseline: %v", err)
+		}
+		// 非 table 格式時比較結果與進度訊息一起寫到 stderr
+		w := o.w
+		if !o.table() {
+			w = o.progress
+		}
+		regressed = compareReports(w, base, &o.report, threshold, alpha)
+	}
+	if err := closeOut(); err != nil {
+		log.Fatalf("write results: %v", err)
+	}
+	if regressed {
+		os.Exit(1)
 	}
 }
 
@@ -125,8 +207,8 @@ func collectBenchFilesFromDir(dir string) ([]string, error) {
 }
 
 // runBatchBenchmark 對多個 benchmark 檔案執行測試並匯總統計
-func runBatchBenchmark(benchPaths []string, toRun []string, runs int, seed int64, splayP, rebuildP float64) {
-	fmt.Printf("Testing %d benchmark files...\n\n", len(benchPaths))
+func runBatchBenchmark(o *output, benchPaths []string, toRun []string, runs int, seed int64, cfg registry.Config, latency bool) {
+	fmt.Fprintf(o.progress, "Testing %d benchmark files...\n\n", len(benchPaths))
 
 	// 為每個實作方式收集所有檔案的統計數據
 	type implStats struct {
@@ -136,6 +218,13 @@ func runBatchBenchmark(benchPaths []string, toRun []string, runs int, seed int64
 		opsList   []int
 		stepsList []float64
 		totalRuns int
+		latency   opLatency
+
+		bytesPerOpList  []float64
+		allocsPerOpList []float64
+		liveList        []float64
+		bytesPerKeyList []float64
+		ptrsPerKeyList  []float64
 	}
 
 	allStats := make(map[string]*implStats)
@@ -151,43 +240,67 @@ func runBatchBenchmark(benchPaths []string, toRun []string, runs int, seed int64
 
 	// 對每個 benchmark 檔案執行測試
 	for idx, benchPath := range benchPaths {
-		fmt.Printf("[%d/%d] Testing: %s\n", idx+1, len(benchPaths), filepath.Base(benchPath))
+		fmt.Fprintf(o.progress, "[%d/%d] Testing: %s\n", idx+1, len(benchPaths), filepath.Base(benchPath))
 
-		bf, err := datastream.ReadBenchFile(benchPath)
+		view, err := datastream.OpenBenchView(benchPath)
 		if err != nil {
 			log.Printf("  ERROR reading bench file: %v\n", err)
 			continue
 		}
 
-		fmt.Printf("  ops: %d, entropy: %.6f\n", len(bf.Ops), computeEntropy(bf.Dist))
+		fmt.Fprintf(o.progress, "  ops: %d, entropy: %.6f\n", view.Len(), computeEntropy(view.Dist()))
+		if meta := view.Meta(); len(meta) > 0 {
+			fmt.Fprintf(o.progress, "  meta: %s\n", meta)
+		}
 
+		fr := newFileReport(benchPath, view)
 		for _, impl := range toRun {
-			fmt.Printf("  - benchmarking %s...\n", impl)
-			stats := benchmarkImpl(bf, impl, runs, seed, splayP, rebuildP)
+			fmt.Fprintf(o.progress, "  - benchmarking %s...\n", impl)
+			stats := benchmarkImpl(view, impl, runs, seed, cfg, latency)
+			fr.Impls = append(fr.Impls, newImplReport(impl, cfg.Describe(impl), view.Len(), stats))
 
 			allStats[impl].avgMsList = append(allStats[impl].avgMsList, stats.avgMs)
 			allStats[impl].minMsList = append(allStats[impl].minMsList, stats.minMs)
 			allStats[impl].maxMsList = append(allStats[impl].maxMsList, stats.maxMs)
-			allStats[impl].opsList = append(allStats[impl].opsList, len(bf.Ops))
+			allStats[impl].opsList = append(allStats[impl].opsList, view.Len())
 			if !math.IsNaN(stats.avgSteps) {
 				allStats[impl].stepsList = append(allStats[impl].stepsList, stats.avgSteps)
 			}
 			allStats[impl].totalRuns += runs
+			if stats.latency != nil {
+				allStats[impl].latency.Merge(stats.latency)
+			}
+
+			bytesPerOp, allocsPerOp, live := stats.memAvg(view.Len())
+			allStats[impl].bytesPerOpList = append(allStats[impl].bytesPerOpList, bytesPerOp)
+			allStats[impl].allocsPerOpList = append(allStats[impl].allocsPerOpList, allocsPerOp)
+			allStats[impl].liveList = append(allStats[impl].liveList, live)
+			if !math.IsNaN(stats.structure.bytesPerKey) {
+				allStats[impl].bytesPerKeyList = append(allStats[impl].bytesPerKeyList, stats.structure.bytesPerKey)
+				allStats[impl].ptrsPerKeyList = append(allStats[impl].ptrsPerKeyList, stats.structure.pointersPerKey)
+			}
 		}
-		fmt.Println()
+		o.addFile(fr)
+		view.Close()
+		fmt.Fprintln(o.progress)
+	}
+	if !o.table() {
+		return
 	}
 
 	// 計算並顯示匯總統計
-	fmt.Println(strings.Repeat("=", 80))
-	fmt.Println("AGGREGATE STATISTICS (across all benchmark files)")
-	fmt.Println(strings.Repeat("=", 80))
+	fmt.Fprintln(o.w, strings.Repeat("=", 80))
+	fmt.Fprintln(o.w, "AGGREGATE STATISTICS (across all benchmark files)")
+	fmt.Fprintln(o.w, strings.Repeat("=", 80))
 
 	rows := make([][]string, 0, len(toRun))
+	lats := make(map[string]*opLatency, len(toRun))
 	for _, impl := range toRun {
 		stats := allStats[impl]
 		if len(stats.avgMsList) == 0 {
 			continue
 		}
+		lats[impl] = &stats.latency
 
 		// 計算平均值
 		avgMs := average(stats.avgMsList)
@@ -209,7 +322,7 @@ func runBatchBenchmark(benchPaths []string, toRun []string, runs int, seed int64
 			steps = fmt.Sprintf("%.6f", average(stats.stepsList))
 		}
 
-		rows = append(rows, []string{
+		row := []string{
 			impl,
 			fmt.Sprintf("%d", stats.totalRuns),
 			fmt.Sprintf("%.3f", avgMs),
@@ -217,39 +330,65 @@ func runBatchBenchmark(benchPaths []string, toRun []string, runs int, seed int64
 			fmt.Sprintf("%.3f", maxMs),
 			fmt.Sprintf("%.2f", avgThr),
 			steps,
-		})
+		}
+
+		// 計算平均記憶體使用
+		structure := structStats{math.NaN(), math.NaN()}
+		if len(stats.bytesPerKeyList) > 0 {
+			structure = structStats{average(stats.bytesPerKeyList), average(stats.ptrsPerKeyList)}
+		}
+		row = append(row, memoryCells(average(stats.bytesPerOpList), average(stats.allocsPerOpList), average(stats.liveList), structure)...)
+		if latency {
+			row = append(row, latencyCells(stats.latency.all())...)
+		}
+		rows = append(rows, row)
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Impl", "Total Runs", "Avg(ms)", "Min(ms)", "Max(ms)", "Avg Ops/s", "AvgSteps"})
-	table.SetAlignment(tablewriter.ALIGN_CENTER)
-	table.SetAutoWrapText(false)
+	header := append([]string{"Impl", "Total Runs", "Avg(ms)", "Min(ms)", "Max(ms)", "Avg Ops/s", "AvgSteps"}, memoryHeader()...)
+	if latency {
+		header = append(header, latencyHeader()...)
+	}
+	table := o.newTable(header)
 	table.AppendBulk(rows)
 	table.Render()
+
+	if latency {
+		fmt.Fprintln(o.w, "LATENCY BY OPERATION (across all benchmark files)")
+		renderLatencyTable(o, toRun, lats)
+	}
 }
 
 // runBenchmark 執行單一 benchmark 檔案的測試
-func runBenchmark(benchPath string, toRun []string, runs int, seed int64, splayP, rebuildP float64) {
-	bf, err := datastream.ReadBenchFile(benchPath)
+func runBenchmark(o *output, benchPath string, toRun []string, runs int, seed int64, cfg registry.Config, latency bool) {
+	view, err := datastream.OpenBenchView(benchPath)
 	if err != nil {
 		log.Printf("ERROR reading bench file %s: %v", benchPath, err)
 		return
 	}
+	defer view.Close()
+
+	fmt.Fprintf(o.progress, "bench_file: %s\n", benchPath)
+	fmt.Fprintf(o.progress, "ops: %d\n", view.Len())
+	fmt.Fprintf(o.progress, "entropy: %.6f\n", computeEntropy(view.Dist()))
+	if meta := view.Meta(); len(meta) > 0 {
+		fmt.Fprintf(o.progress, "meta: %s\n", meta)
+	}
 
-	fmt.Printf("bench_file: %s\n", benchPath)
-	fmt.Printf("ops: %d\n", len(bf.Ops))
-	fmt.Printf("entropy: %.6f\n", computeEntropy(bf.Dist))
+	fr := newFileReport(benchPath, view)
+	defer o.addFile(fr)
 
 	rows := make([][]string, 0, len(toRun))
+	lats := make(map[string]*opLatency, len(toRun))
 	for _, impl := range toRun {
-		fmt.Printf("benchmarking %s...\n", impl)
-		stats := benchmarkImpl(bf, impl, runs, seed, splayP, rebuildP)
-		thr := float64(len(bf.Ops)) / (stats.avgMs / 1000.0)
+		fmt.Fprintf(o.progress, "benchmarking %s...\n", impl)
+		stats := benchmarkImpl(view, impl, runs, seed, cfg, latency)
+		fr.Impls = append(fr.Impls, newImplReport(impl, cfg.Describe(impl), view.Len(), stats))
+		thr := float64(view.Len()) / (stats.avgMs / 1000.0)
 		steps := "N/A"
 		if !math.IsNaN(stats.avgSteps) {
 			steps = fmt.Sprintf("%.6f", stats.avgSteps)
 		}
-		rows = append(rows, []string{
+		row := []string{
 			impl,
 			fmt.Sprintf("%d", runs),
 			fmt.Sprintf("%.3f", stats.avgMs),
@@ -257,15 +396,30 @@ func runBenchmark(benchPath string, toRun []string, runs int, seed int64, splayP
 			fmt.Sprintf("%.3f", stats.maxMs),
 			fmt.Sprintf("%.2f", thr),
 			steps,
-		})
+		}
+		bytesPerOp, allocsPerOp, live := stats.memAvg(view.Len())
+		row = append(row, memoryCells(bytesPerOp, allocsPerOp, live, stats.structure)...)
+		if latency {
+			row = append(row, latencyCells(stats.latency.all())...)
+			lats[impl] = stats.latency
+		}
+		rows = append(rows, row)
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Impl", "Runs", "Avg(ms)", "Min(ms)", "Max(ms)", "Ops/s", "AvgSteps"})
-	table.SetAlignment(tablewriter.ALIGN_CENTER)
-	table.SetAutoWrapText(false)
+	if !o.table() {
+		return
+	}
+	header := append([]string{"Impl", "Runs", "Avg(ms)", "Min(ms)", "Max(ms)", "Ops/s", "AvgSteps"}, memoryHeader()...)
+	if latency {
+		header = append(header, latencyHeader()...)
+	}
+	table := o.newTable(header)
 	table.AppendBulk(rows)
 	table.Render()
+
+	if latency {
+		renderLatencyTable(o, toRun, lats)
+	}
 }
 
 // 輔助函數：計算平均值
@@ -309,27 +463,68 @@ func max(values []float64) float64 {
 }
 
 type benchStats struct {
-	avgMs    float64
-	minMs    float64
-	maxMs    float64
-	avgSteps float64 // from one run (structure-dependent), NaN if not analyzable
+	avgMs     float64
+	minMs     float64
+	maxMs     float64
+	avgSteps  float64     // from one run (structure-dependent), NaN if not analyzable
+	latency   *opLatency  // merged over all runs, nil without -latency
+	structure structStats // from one run, NaN if the nodes cannot be traversed
+
+	runMs      []float64      // per run, in run order
+	runLatency []*latencyHist // per run, all operation types, nil without -latency
+	runMem     []memSample    // per run
 }
 
-func benchmarkImpl(bf *datastream.BenchFile, impl string, runs int, seed int64, splayP, rebuildP float64) benchStats {
+// memAvg 回傳各次執行記憶體量測的平均
+func (s benchStats) memAvg(ops int) (bytesPerOp, allocsPerOp, liveBytes float64) {
+	for _, m := range s.runMem {
+		bytesPerOp += m.bytesPerOp(ops)
+		allocsPerOp += m.allocsPerOp(ops)
+		liveBytes += float64(m.liveBytes)
+	}
+	n := float64(len(s.runMem))
+	return bytesPerOp / n, allocsPerOp / n, liveBytes / n
+}
+
+// benchmarkImpl 重播 runs 次並量測耗時與記憶體；latency 為 true 時每次執行後
+// 另以新建的實作再重播一次記錄每個操作的延遲，不影響耗時的量測
+func benchmarkImpl(view *datastream.BenchView, impl string, runs int, seed int64, cfg registry.Config, latency bool) benchStats {
 	durations := make([]float64, 0, runs)
 	var sampleSteps = math.NaN()
+	var lat *opLatency
+	var runLatency []*latencyHist
+	if latency {
+		lat = new(opLatency)
+		runLatency = make([]*latencyHist, 0, runs)
+	}
+	runMem := make([]memSample, 0, runs)
+	structure := structStats{math.NaN(), math.NaN()}
+	var probe memProbe
 	for i := 0; i < runs; i++ {
 		// fmt.Printf("running %s %d\n", impl, i)
-		sl := newImpl(impl, seed, splayP, rebuildP)
-		elapsed := runOpsAndTime(sl, bf)
+		probe.reset()
+		sl := newImpl(impl, view.Dist(), seed, cfg)
+		probe.begin()
+		elapsed := runOpsAndTime(sl, view)
+		runMem = append(runMem, probe.end(sl))
 		durations = append(durations, float64(elapsed.Microseconds())/1000.0)
+		if i == 0 {
+			structure = measureStruct(sl)
+		}
 		if math.IsNaN(sampleSteps) {
 			if analy, ok := sl.(skiplist.Analyable); ok {
-				s, _ := analyTool.AnalyzeStep(analy, bf.Dist)
+				s, _ := analyTool.AnalyzeStep(analy, view.Dist())
 				sampleSteps = s
 			}
 		}
+		if latency {
+			runLat := new(opLatency)
+			runOpsLatency(newImpl(impl, view.Dist(), seed, cfg), view, runLat)
+			lat.Merge(runLat)
+			runLatency = append(runLatency, runLat.all())
+		}
 	}
+	runMs := slices.Clone(durations)
 	sort.Float64s(durations)
 	sum := 0.0
 	for _, v := range durations {
@@ -337,54 +532,54 @@ func benchmarkImpl(bf *datastream.BenchFile, impl string, runs int, seed int64,
 	}
 	avg := sum / float64(len(durations))
 	return benchStats{
-		avgMs:    avg,
-		minMs:    durations[0],
-		maxMs:    durations[len(durations)-1],
-		avgSteps: sampleSteps,
+		avgMs:      avg,
+		minMs:      durations[0],
+		maxMs:      durations[len(durations)-1],
+		avgSteps:   sampleSteps,
+		latency:    lat,
+		structure:  structure,
+		runMs:      runMs,
+		runLatency: runLatency,
+		runMem:     runMem,
 	}
 }
 
-func newImpl(impl string, seed int64, splayP, rebuildP float64) skiplist.SkipList {
-	switch impl {
-	case "basic":
-		return basic.NewBasicSkipList(seed)
-	case "splay":
-		return splay.NewSplayList(splayP)
-	case "la":
-		return la.NewLASkipList(seed)
-	// case "rebuild":
-	// 	return rebuildsl.NewRebuildSLList(rebuildP)
-	// case "gravity":
-	// 	return gravity.NewGravityList()
-	// case "falldown":
-	// 	return falldown.NewFdList()
-	default:
-		log.Fatalf("unknown -impl: %s", impl)
-		return nil
+// newImpl 依 registry 建立實作，參數已在啟動時檢查過
+func newImpl(impl string, dist map[skiplist.K]float64, seed int64, cfg registry.Config) skiplist.SkipList {
+	sl, err := cfg.New(impl, registry.Env{Seed: seed, Dist: dist})
+	if err != nil {
+		log.Fatalf("create %s: %v", impl, err)
 	}
+	return sl
 }
 
-func runOpsAndTime(sl skiplist.SkipList, bf *datastream.BenchFile) time.Duration {
-	// 預先決定插入策略，避免每次操作都做類型斷言
-	insertFunc := func(key skiplist.K) {
-		val := bf.Dist[key]
-		sl.Put(key, skiplist.V(val))
-	}
+// newInserter 回傳插入 key 的函式，權重取自 dist；la 以 PutWithNP 帶入 np。
+// 預先決定插入策略，避免每次操作都做類型斷言
+func newInserter(sl skiplist.SkipList, dist map[skiplist.K]float64) func(skiplist.K) {
 	if laSl, ok := sl.(laPutWithNP); ok {
-		n := float64(len(bf.Dist))
-		insertFunc = func(key skiplist.K) {
-			val := bf.Dist[key]
+		n := float64(len(dist))
+		return func(key skiplist.K) {
+			val := dist[key]
 			laSl.PutWithNP(key, skiplist.V(val), val*n)
 		}
 	}
+	return func(key skiplist.K) {
+		sl.Put(key, skiplist.V(dist[key]))
+	}
+}
 
+// runOpsAndTime 依序重播 view 中的操作並回傳總耗時，計時區間內只讀取開始與結束的時間；
+// view 的分頁在開檔時已載入，計時區間內只有解碼，不會讀檔
+func runOpsAndTime(sl skiplist.SkipList, view *datastream.BenchView) time.Duration {
+	insert := newInserter(sl, view.Dist())
 	start := time.Now()
-	for _, op := range bf.Ops {
+	for i := range view.Len() {
+		op := view.Op(i)
 		switch op.Type {
 		case datastream.OpQuery:
 			sl.Get(op.Key)
 		case datastream.OpInsert:
-			insertFunc(op.Key)
+			insert(op.Key)
 		case datastream.OpDelete:
 			sl.Delete(op.Key)
 		}
@@ -392,28 +587,25 @@ func runOpsAndTime(sl skiplist.SkipList, bf *datastream.BenchFile) time.Duration
 	return time.Since(start)
 }
 
-func parseImpls(s string) []string {
-	if s == "" || s == "all" {
-		return []string{"basic", "splay", "la"}
-	}
-	parts := strings.Split(s, ",")
-	out := make([]string, 0, len(parts))
-	seen := map[string]bool{}
-	for _, p := range parts {
-		t := strings.TrimSpace(strings.ToLower(p))
-		if t == "" || seen[t] {
-			continue
-		}
-		switch t {
-		case "basic", "splay", "la", "rebuild", "gravity", "falldown":
-			out = append(out, t)
-			seen[t] = true
+// runOpsLatency 與 runOpsAndTime 相同地重播，但每個操作的延遲依種類記錄在 lat。
+// 相鄰操作共用同一次時間讀取，每個操作只多一次 time.Now 的成本
+func runOpsLatency(sl skiplist.SkipList, view *datastream.BenchView, lat *opLatency) {
+	insert := newInserter(sl, view.Dist())
+	prev := time.Now()
+	for i := range view.Len() {
+		op := view.Op(i)
+		switch op.Type {
+		case datastream.OpQuery:
+			sl.Get(op.Key)
+		case datastream.OpInsert:
+			insert(op.Key)
+		case datastream.OpDelete:
+			sl.Delete(op.Key)
 		}
+		now := time.Now()
+		lat[op.Type].Record(now.Sub(prev))
+		prev = now
 	}
-	if len(out) == 0 {
-		return []string{"basic", "splay", "la"}
-	}
-	return out
 }
 
 func computeEntropy(m map[skiplist.K]float64) float64 {
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/analyTool"
+)
+
+// memSample 為一次重播以 runtime.MemStats 量得的記憶體使用
+type memSample struct {
+	allocBytes uint64 // 重播期間配置的位元組數
+	allocs     uint64 // 重播期間配置的次數
+	liveBytes  int64  // 重播結束並 GC 後仍存活的 heap，相對於建立實作之前
+}
+
+func (m memSample) bytesPerOp(ops int) float64 {
+	return float64(m.allocBytes) / float64(ops)
+}
+
+func (m memSample) allocsPerOp(ops int) float64 {
+	return float64(m.allocs) / float64(ops)
+}
+
+// memProbe 記錄建立實作前與重播前的 heap 狀態，量測本身不在計時區間內
+type memProbe struct {
+	base  runtime.MemStats
+	start runtime.MemStats
+}
+
+// reset 先 GC 再記錄基準，需在建立實作之前呼叫
+func (p *memProbe) reset() {
+	runtime.GC()
+	runtime.ReadMemStats(&p.base)
+}
+
+// begin 在重播開始前呼叫
+func (p *memProbe) begin() {
+	runtime.ReadMemStats(&p.start)
+}
+
+// end 在重播結束後呼叫，sl 需保持存活到 GC 之後才能算進存活的 heap
+func (p *memProbe) end(sl skiplist.SkipList) memSample {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	s := memSample{
+		allocBytes: m.TotalAlloc - p.start.TotalAlloc,
+		allocs:     m.Mallocs - p.start.Mallocs,
+	}
+	runtime.GC()
+	runtime.ReadMemStats(&m)
+	s.liveBytes = int64(m.HeapAlloc) - int64(p.base.HeapAlloc)
+	runtime.KeepAlive(sl)
+	return s
+}
+
+// structStats 為沿 Nodelike 走訪估計的結構大小，無法走訪（GetHead 為 nil）時為 NaN
+type structStats struct {
+	bytesPerKey    float64
+	pointersPerKey float64
+}
+
+func measureStruct(sl skiplist.SkipList) structStats {
+	f := analyTool.MeasureFootprint(sl)
+	if f.Nodes == 0 || sl.Len() == 0 {
+		return structStats{math.NaN(), math.NaN()}
+	}
+	b, p := f.PerKey(sl.Len())
+	return structStats{b, p}
+}
+
+// memoryHeader 回傳記憶體欄位的標題
+func memoryHeader() []string {
+	return []string{"B/op", "Allocs/op", "Live(KB)", "B/key", "Ptr/key"}
+}
+
+// memoryCells 回傳對應 memoryHeader 的欄位值
+func memoryCells(bytesPerOp, allocsPerOp, liveBytes float64, st structStats) []string {
+	return []string{
+		fmt.Sprintf("%.1f", bytesPerOp),
+		fmt.Sprintf("%.3f", allocsPerOp),
+		fmt.Sprintf("%.1f", liveBytes/1024),
+		formatNaN("%.1f", st.bytesPerKey),
+		formatNaN("%.2f", st.pointersPerKey),
+	}
+}
+
+func formatNaN(format string, v float64) string {
+	if math.IsNaN(v) {
+		return "N/A"
+	}
+	return fmt.Sprintf(format, v)
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Hakuto4838/SkipList.git/datastream"
+	"github.com/olekukonko/tablewriter"
+)
+
+// report 收集所有 bench 檔的結果，供 -format json|csv 輸出
+type report struct {
+	Seed  int64         `json:"seed"`
+	Files []*fileReport `json:"files"`
+}
+
+type fileReport struct {
+	File    string         `json:"file"`
+	Meta    map[string]any `json:"meta,omitempty"` // bench 檔的中繼資料，version 1 的檔案沒有此欄
+	N       int            `json:"n"`              // Dist 中的 key 數
+	Ops     int            `json:"ops"`
+	Entropy float64        `json:"entropy"`
+	Impls   []*implReport  `json:"impls"`
+
+	meta datastream.Metadata // 保留寫入順序供 csv 使用
+}
+
+type implReport struct {
+	Impl               string                     `json:"impl"`
+	Params             map[string]string          `json:"params"`
+	Threads            int                        `json:"threads"`
+	AvgMs              float64                    `json:"avg_ms"`
+	MinMs              float64                    `json:"min_ms"`
+	MaxMs              float64                    `json:"max_ms"`
+	OpsPerSec          float64                    `json:"ops_per_sec"`
+	OpsPerSecPerThread float64                    `json:"ops_per_sec_per_thread,omitempty"`
+	AvgSteps           *float64                   `json:"avg_steps,omitempty"` // 不可分析的實作沒有此欄
+	Latency            map[string]*latencySummary `json:"latency,omitempty"`   // 依操作種類，"All" 為全部操作，需 -latency
+	Memory             *memorySummary             `json:"memory,omitempty"`    // 各次執行的平均，多執行緒模式沒有此欄
+	Runs               []runReport                `json:"runs"`
+}
+
+type runReport struct {
+	Run       int             `json:"run"`
+	Ms        float64         `json:"ms"`
+	OpsPerSec float64         `json:"ops_per_sec"`
+	Latency   *latencySummary `json:"latency,omitempty"`
+	Memory    *memorySummary  `json:"memory,omitempty"`
+}
+
+type memorySummary struct {
+	AllocBytesPerOp float64  `json:"alloc_bytes_per_op"`
+	AllocsPerOp     float64  `json:"allocs_per_op"`
+	LiveBytes       float64  `json:"live_bytes"`
+	BytesPerKey     *float64 `json:"bytes_per_key,omitempty"` // 無法走訪節點的實作沒有此欄
+	PointersPerKey  *float64 `json:"pointers_per_key,omitempty"`
+}
+
+func newMemorySummary(bytesPerOp, allocsPerOp, liveBytes float64, st structStats) *memorySummary {
+	m := &memorySummary{AllocBytesPerOp: bytesPerOp, AllocsPerOp: allocsPerOp, LiveBytes: liveBytes}
+	if !math.IsNaN(st.bytesPerKey) {
+		b, p := st.bytesPerKey, st.pointersPerKey
+		m.BytesPerKey, m.PointersPerKey = &b, &p
+	}
+	return m
+}
+
+type latencySummary struct {
+	Count uint64 `json:"count"`
+	P50   int64  `json:"p50_ns"`
+	P90   int64  `json:"p90_ns"`
+	P99   int64  `json:"p99_ns"`
+	P999  int64  `json:"p99_9_ns"`
+	Max   int64  `json:"max_ns"`
+}
+
+func summarize(h *latencyHist) *latencySummary {
+	return &latencySummary{
+		Count: h.Count(),
+		P50:   h.Percentile(50).Nanoseconds(),
+		P90:   h.Percentile(90).Nanoseconds(),
+		P99:   h.Percentile(99).Nanoseconds(),
+		P999:  h.Percentile(99.9).Nanoseconds(),
+		Max:   h.Max().Nanoseconds(),
+	}
+}
+
+func newFileReport(path string, view *datastream.BenchView) *fileReport {
+	return &fileReport{
+		File:    path,
+		Meta:    view.Meta().Map(),
+		N:       view.DistLen(),
+		Ops:     view.Len(),
+		Entropy: computeEntropy(view.Dist()),
+		meta:    view.Meta(),
+	}
+}
+
+// newImplReport 由單執行緒的 benchStats 建立結果
+func newImplReport(impl string, params map[string]string, ops int, stats benchStats) *implReport {
+	bytesPerOp, allocsPerOp, liveBytes := stats.memAvg(ops)
+	r := &implReport{
+		Impl:      impl,
+		Params:    params,
+		Threads:   1,
+		AvgMs:     stats.avgMs,
+		MinMs:     stats.minMs,
+		MaxMs:     stats.maxMs,
+		OpsPerSec: float64(ops) / (stats.avgMs / 1000.0),
+		Memory:    newMemorySummary(bytesPerOp, allocsPerOp, liveBytes, stats.structure),
+	}
+	if !math.IsNaN(stats.avgSteps) {
+		steps := stats.avgSteps
+		r.AvgSteps = &steps
+	}
+	if stats.latency != nil {
+		r.Latency = map[string]*latencySummary{"All": summarize(stats.latency.all())}
+		for i := range stats.latency {
+			if h := &stats.latency[i]; h.Count() > 0 {
+				r.Latency[datastream.OperationType(i).String()] = summarize(h)
+			}
+		}
+	}
+	for i, ms := range stats.runMs {
+		m := stats.runMem[i]
+		run := runReport{
+			Run:       i + 1,
+			Ms:        ms,
+			OpsPerSec: float64(ops) / (ms / 1000.0),
+			Memory:    newMemorySummary(m.bytesPerOp(ops), m.allocsPerOp(ops), float64(m.liveBytes), stats.structure),
+		}
+		if stats.runLatency != nil {
+			run.Latency = summarize(stats.runLatency[i])
+		}
+		r.Runs = append(r.Runs, run)
+	}
+	return r
+}
+
+// newParallelReport 由多執行緒的 parallelStats 建立結果
+func newParallelReport(impl string, params map[string]string, threads, ops int, stats parallelStats) *implReport {
+	r := &implReport{
+		Impl:               impl,
+		Params:             params,
+		Threads:            threads,
+		AvgMs:              stats.avgMs,
+		MinMs:              stats.minMs,
+		MaxMs:              stats.maxMs,
+		OpsPerSec:          float64(ops) / (stats.avgMs / 1000.0),
+		OpsPerSecPerThread: stats.thrPerThread,
+	}
+	for i, ms := range stats.runMs {
+		r.Runs = append(r.Runs, runReport{Run: i + 1, Ms: ms, OpsPerSec: float64(ops) / (ms / 1000.0)})
+	}
+	return r
+}
+
+// output 決定結果的格式與去處。table 以外的格式在最後一次寫出，
+// 進度訊息改寫到 stderr，避免混入輸出
+type output struct {
+	format   string
+	w        io.Writer
+	progress io.Writer
+	report   report
+}
+
+// newOutput 依 -format 與 -o 建立 output，path 為空時寫到 stdout
+func newOutput(format, path string, seed int64) (*output, func() error) {
+	switch format {
+	case "table", "json", "csv":
+	default:
+		log.Fatalf("invalid -format: %s", format)
+	}
+	o := &output{format: format, w: os.Stdout, progress: os.Stdout, report: report{Seed: seed}}
+	if format != "table" {
+		o.progress = os.Stderr
+	}
+	closeFn := func() error { return nil }
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			log.Fatalf("create %s: %v", path, err)
+		}
+		o.w = f
+		closeFn = f.Close
+	}
+	return o, closeFn
+}
+
+func (o *output) table() bool {
+	return o.format == "table"
+}
+
+// newTable 建立寫到結果去處的表格
+func (o *output) newTable(header []string) *tablewriter.Table {
+	return newTable(o.w, header)
+}
+
+// newTable 建立共用置中格式的表格
+func newTable(w io.Writer, header []string) *tablewriter.Table {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+	table.SetAutoWrapText(false)
+	return table
+}
+
+func (o *output) addFile(fr *fileReport) {
+	o.report.Files = append(o.report.Files, fr)
+}
+
+// flush 以 json 或 csv 寫出收集到的結果
+func (o *output) flush() error {
+	switch o.format {
+	case "json":
+		enc := json.NewEncoder(o.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(&o.report)
+	case "csv":
+		return o.writeCSV()
+	}
+	return nil
+}
+
+// writeCSV 每次執行一列，bench 檔與實作的資訊重複填在每一列
+func (o *output) writeCSV() error {
+	w := csv.NewWriter(o.w)
+	w.Write([]string{
+		"file", "n", "ops", "entropy", "impl", "params", "seed", "threads", "run",
+		"ms", "ops_per_sec", "avg_steps", "p50_ns", "p90_ns", "p99_ns", "p99_9_ns", "max_ns",
+		"alloc_bytes_per_op", "allocs_per_op", "live_bytes", "bytes_per_key", "pointers_per_key", "meta",
+	})
+	for _, fr := range o.report.Files {
+		for _, ir := range fr.Impls {
+			steps := ""
+			if ir.AvgSteps != nil {
+				steps = strconv.FormatFloat(*ir.AvgSteps, 'f', 6, 64)
+			}
+			for _, run := range ir.Runs {
+				lat := make([]string, 5)
+				if l := run.Latency; l != nil {
+					lat = []string{itoa64(l.P50), itoa64(l.P90), itoa64(l.P99), itoa64(l.P999), itoa64(l.Max)}
+				}
+				mem := make([]string, 5)
+				if m := run.Memory; m != nil {
+					mem = []string{
+						strconv.FormatFloat(m.AllocBytesPerOp, 'f', 3, 64),
+						strconv.FormatFloat(m.AllocsPerOp, 'f', 6, 64),
+						strconv.FormatFloat(m.LiveBytes, 'f', 0, 64),
+						formatOptional(m.BytesPerKey),
+						formatOptional(m.PointersPerKey),
+					}
+				}
+				w.Write(slices.Concat([]string{
+					fr.File,
+					strconv.Itoa(fr.N),
+					strconv.Itoa(fr.Ops),
+					strconv.FormatFloat(fr.Entropy, 'f', 6, 64),
+					ir.Impl,
+					formatParams(ir.Params),
+					strconv.FormatInt(o.report.Seed, 10),
+					strconv.Itoa(ir.Threads),
+					strconv.Itoa(run.Run),
+					strconv.FormatFloat(run.Ms, 'f', 3, 64),
+					strconv.FormatFloat(run.OpsPerSec, 'f', 2, 64),
+					steps,
+				}, lat, mem, []string{fr.meta.Format(";")}))
+			}
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// formatOptional 將 nil 輸出為空字串
+func formatOptional(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', 3, 64)
+}
+
+func itoa64(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+// formatParams 以 key=value;key=value 依 key 排序輸出
+func formatParams(params map[string]string) string {
+	parts := make([]string, 0, len(params))
+	for _, k := range slices.Sorted(maps.Keys(params)) {
+		parts = append(parts, fmt.Sprintf("%s=%s", k, params[k]))
+	}
+	return strings.Join(parts, ";")
+}
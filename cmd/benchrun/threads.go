@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Hakuto4838/SkipList.git/datastream"
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/registry"
+	"github.com/Hakuto4838/SkipList.git/skiplist/syncsl"
+)
+
+// parseThreads 解析 -threads，可為逗號分隔的 goroutine 數，
+// 單一的 N 會展開為 1, 2, 4, ... , N 以取得擴展曲線
+func parseThreads(s string) []int {
+	var out []int
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		n, err := strconv.Atoi(p)
+		if err != nil || n <= 0 {
+			log.Fatalf("invalid -threads: %s", s)
+		}
+		out = append(out, n)
+	}
+	if len(out) == 1 {
+		n := out[0]
+		out = out[:0]
+		for t := 1; t < n; t *= 2 {
+			out = append(out, t)
+		}
+		out = append(out, n)
+	}
+	if len(out) == 0 {
+		return []int{1}
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// newConcurrentImpl 建立可供多個 goroutine 使用的實作，本身不是執行緒安全的實作以 syncsl 包裝；
+// 回傳的插入函式與單執行緒相同由 newInserter 選擇（如 la 使用 PutWithNP），包裝時在寫鎖內呼叫
+func newConcurrentImpl(impl string, dist map[skiplist.K]float64, seed int64, cfg registry.Config) (skiplist.SkipList, func(skiplist.K)) {
+	sl := newImpl(impl, dist, seed, cfg)
+	insert := newInserter(sl, dist)
+	info, _ := registry.Lookup(impl)
+	if info.ThreadSafe {
+		return sl, insert
+	}
+	wrapped := syncsl.NewSyncSkipList(sl, info.SyncMode())
+	return wrapped, func(key skiplist.K) {
+		wrapped.Update(func(skiplist.SkipListOf[skiplist.K, skiplist.V]) { insert(key) })
+	}
+}
+
+// runOpsParallel 將 view 的操作交錯分給 threads 個 goroutine（第 i 個取第 i, i+threads, ... 個操作）同時重播，
+// 插入使用 insert，回傳所有 goroutine 完成的總時間與各 goroutine 自己的耗時
+func runOpsParallel(sl skiplist.SkipList, insert func(skiplist.K), view *datastream.BenchView, threads int) (time.Duration, []time.Duration) {
+	perThread := make([]time.Duration, threads)
+	var ready, done sync.WaitGroup
+	start := make(chan struct{})
+	for w := 0; w < threads; w++ {
+		ready.Add(1)
+		done.Add(1)
+		go func(w int) {
+			defer done.Done()
+			ready.Done()
+			<-start
+			begin := time.Now()
+			for i := w; i < view.Len(); i += threads {
+				op := view.Op(i)
+				switch op.Type {
+				case datastream.OpQuery:
+					sl.Get(op.Key)
+				case datastream.OpInsert:
+					insert(op.Key)
+				case datastream.OpDelete:
+					sl.Delete(op.Key)
+				}
+			}
+			perThread[w] = time.Since(begin)
+		}(w)
+	}
+	// 等所有 goroutine 就緒後才開始計時，避免把建立 goroutine 的時間算進去
+	ready.Wait()
+	begin := time.Now()
+	close(start)
+	done.Wait()
+	return time.Since(begin), perThread
+}
+
+type parallelStats struct {
+	avgMs        float64
+	minMs        float64
+	maxMs        float64
+	thrPerThread float64   // 有分到操作的各 goroutine 自身 ops/s 的平均
+	runMs        []float64 // 依執行順序
+}
+
+func benchmarkParallel(view *datastream.BenchView, impl string, threads, runs int, seed int64, cfg registry.Config) parallelStats {
+	durations := make([]float64, 0, runs)
+	thrSum := 0.0
+	workers := 0 // 有分到操作且耗時可量測的 goroutine 數
+	for i := 0; i < runs; i++ {
+		sl, insert := newConcurrentImpl(impl, view.Dist(), seed, cfg)
+		elapsed, perThread := runOpsParallel(sl, insert, view, threads)
+		durations = append(durations, float64(elapsed.Microseconds())/1000.0)
+		for w, d := range perThread {
+			// threads 多於操作數時部分 goroutine 沒有操作，不計入平均
+			ops := (view.Len() - w + threads - 1) / threads
+			if ops <= 0 || d <= 0 {
+				continue
+			}
+			thrSum += float64(ops) / d.Seconds()
+			workers++
+		}
+	}
+	thrPerThread := 0.0
+	if workers > 0 {
+		thrPerThread = thrSum / float64(workers)
+	}
+	runMs := slices.Clone(durations)
+	slices.Sort(durations)
+	return parallelStats{
+		avgMs:        average(durations),
+		minMs:        durations[0],
+		maxMs:        durations[len(durations)-1],
+		thrPerThread: thrPerThread,
+		runMs:        runMs,
+	}
+}
+
+// runScalingBenchmark 對每個 bench 檔與實作，依序以 threadCounts 中的 goroutine 數重播，
+// Speedup 為相對於最少 goroutine 數的總吞吐量倍數
+func runScalingBenchmark(o *output, benchPaths []string, toRun []string, threadCounts []int, runs int, seed int64, cfg registry.Config) {
+	for idx, benchPath := range benchPaths {
+		view, err := datastream.OpenBenchView(benchPath)
+		if err != nil {
+			log.Printf("ERROR reading bench file %s: %v", benchPath, err)
+			continue
+		}
+		fmt.Fprintf(o.progress, "[%d/%d] bench_file: %s\n", idx+1, len(benchPaths), benchPath)
+		fmt.Fprintf(o.progress, "ops: %d, entropy: %.6f\n", view.Len(), computeEntropy(view.Dist()))
+		if meta := view.Meta(); len(meta) > 0 {
+			fmt.Fprintf(o.progress, "meta: %s\n", meta)
+		}
+
+		fr := newFileReport(benchPath, view)
+		o.addFile(fr)
+
+		rows := make([][]string, 0, len(toRun)*len(threadCounts))
+		for _, impl := range toRun {
+			baseThr := 0.0
+			for _, threads := range threadCounts {
+				fmt.Fprintf(o.progress, "benchmarking %s with %d goroutines...\n", impl, threads)
+				stats := benchmarkParallel(view, impl, threads, runs, seed, cfg)
+				fr.Impls = append(fr.Impls, newParallelReport(impl, cfg.Describe(impl), threads, view.Len(), stats))
+				thr := float64(view.Len()) / (stats.avgMs / 1000.0)
+				if baseThr == 0 {
+					baseThr = thr
+				}
+				rows = append(rows, []string{
+					impl,
+					fmt.Sprintf("%d", threads),
+					fmt.Sprintf("%d", runs),
+					fmt.Sprintf("%.3f", stats.avgMs),
+					fmt.Sprintf("%.3f", stats.minMs),
+					fmt.Sprintf("%.3f", stats.maxMs),
+					fmt.Sprintf("%.2f", thr),
+					fmt.Sprintf("%.2f", stats.thrPerThread),
+					fmt.Sprintf("%.2fx", thr/baseThr),
+				})
+			}
+		}
+
+		view.Close()
+
+		if !o.table() {
+			continue
+		}
+		table := o.newTable([]string{"Impl", "Threads", "Runs", "Avg(ms)", "Min(ms)", "Max(ms)", "Ops/s", "Ops/s/Thread", "Speedup"})
+		table.AppendBulk(rows)
+		table.Render()
+		fmt.Fprintln(o.w)
+	}
+}
@@ -1,72 +1,101 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"log"
 
 	"github.com/Hakuto4838/SkipList.git/datastream"
 	"github.com/Hakuto4838/SkipList.git/skiplist"
 	"github.com/Hakuto4838/SkipList.git/skiplist/analyTool"
-	"github.com/Hakuto4838/SkipList.git/skiplist/basic"
-	"github.com/Hakuto4838/SkipList.git/skiplist/la"
-	"github.com/Hakuto4838/SkipList.git/skiplist/rebuildsl"
-	"github.com/Hakuto4838/SkipList.git/skiplist/splay"
+	"github.com/Hakuto4838/SkipList.git/skiplist/registry"
+
+	// 以空白匯入登錄 -impl 可選的實作
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/Tlist"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/basic"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/falldown"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/gravity"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/la"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/lockfree"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/rebuildsl"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/sharded"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/splay"
 )
 
-func insertSequential(sl skiplist.SkipList, gen *datastream.ZipfDataGenerator) {
-	for k, v := range gen.GetKeyMap() {
-		sl.Put(k, v)
+type laPutWithNP interface {
+	PutWithNP(key skiplist.K, value skiplist.V, np float64) (skiplist.V, bool)
+}
+
+type forceBalancer interface {
+	ForceBalance(limit int)
+}
+
+// insertSequential 插入所有 key，支援預估頻率的實作帶入 np=n*prob
+func insertSequential(sl skiplist.SkipList, kmap map[skiplist.K]float64) {
+	n := float64(len(kmap))
+	for k, prob := range kmap {
+		if laSl, ok := sl.(laPutWithNP); ok {
+			laSl.PutWithNP(k, prob*n, prob*n)
+			continue
+		}
+		sl.Put(k, prob)
 	}
 }
 
 func testOne(name string, sl skiplist.SkipList, kmap map[skiplist.K]float64) {
 	fmt.Printf("=== %s ===\n", name)
-	// analyTool.CountLevel(sl.(skiplist.Analyable))
-	score, _ := analyTool.AnalyzeStep(sl.(skiplist.Analyable), kmap)
+	analy, ok := sl.(skiplist.Analyable)
+	if !ok {
+		fmt.Printf("%s is not analyzable\n\n", name)
+		return
+	}
+	// analyTool.CountLevel(analy)
+	score, _ := analyTool.AnalyzeStep(analy, kmap)
 	fmt.Printf("score: %.6f\n\n", score)
-	analyTool.PrintSkipList(sl.(skiplist.Analyable), 8, 35)
+	analyTool.PrintSkipList(analy, 8, 35)
 }
 
 func main() {
-	const n = 900
-	const seed = 42
+	var n int
+	var seed int64
+	var impls string
+	// splay 在此以 p=1 觀察完全調整後的結構
+	cfg := registry.Config{"splay": {"p": "1"}}
 
-	// Zipf distribution for analysis
-	gen := datastream.NewZipfDataGenerator(n, 1.07, 1.0, seed)
-	kmap := gen.GetKeyMap()
-
-	// Basic
-	basicSL := basic.NewBasicSkipList(seed)
-	insertSequential(basicSL, gen)
-	testOne("basic", basicSL, kmap)
-
-	// Splay, 先插入再進行 training 查詢
-	splaySL := splay.NewSplayList(1)
-	insertSequential(splaySL, gen)
+	flag.IntVar(&n, "n", 900, "number of keys for the Zipf generator")
+	flag.Int64Var(&seed, "seed", 42, "seed for the generator and structures")
+	flag.StringVar(&impls, "impl", "basic,splay,la,rebuild", "implementations to compare: all or comma list")
+	flag.Var(cfg, "param", "implementation parameter impl.key=value, may be repeated (splay.p defaults to 1 here)")
+	flag.Usage = func() {
+		flag.PrintDefaults()
+		registry.PrintUsage(flag.CommandLine.Output())
+	}
+	flag.Parse()
 
-	// 執行隨機查詢作為 training（Zipf 模擬熱點）
-	seq := gen.GenerateSequence(n * 10) // 20 倍查詢量
-	for _, idx := range seq {
-		splaySL.Get(skiplist.K(idx))
+	toRun, err := registry.ParseNames(impls)
+	if err != nil {
+		log.Fatalf("invalid -impl: %v", err)
 	}
 
-	testOne("splay", splaySL, kmap)
+	// Zipf distribution for analysis
+	gen := datastream.NewZipfDataGenerator(n, 1.07, 1.0, seed)
+	kmap := gen.GetKeyMap()
 
-	// LA SkipList
-	laSL := la.NewLASkipList(seed)
-	// 依 Zipf 權重插入並帶入預估頻率 np=n*prob
-	for k, prob := range kmap {
-		np := float64(n) * prob
-		laSL.PutWithNP(k, prob*float64(n), np)
-	}
-	testOne("la", laSL, kmap)
+	// 執行隨機查詢作為 training（Zipf 模擬熱點），不會調整結構的實作不受影響
+	seq := gen.GenerateSequence(n * 10) // 10 倍查詢量
 
-	// Rebuild
-	rebuildSL := rebuildsl.NewRebuildSLList(0.1)
-	insertSequential(rebuildSL, gen)
-	for _, idx := range seq {
-		rebuildSL.Get(skiplist.K(idx))
+	for _, name := range toRun {
+		sl, err := cfg.New(name, registry.Env{Seed: seed, Dist: kmap})
+		if err != nil {
+			log.Fatalf("create %s: %v", name, err)
+		}
+		insertSequential(sl, kmap)
+		for _, idx := range seq {
+			sl.Get(skiplist.K(idx))
+		}
+		if fb, ok := sl.(forceBalancer); ok {
+			fb.ForceBalance(32)
+		}
+		testOne(name, sl, kmap)
 	}
-	rebuildSL.ForceBalance(32)
-	testOne("rebuild", rebuildSL, kmap)
-
 }
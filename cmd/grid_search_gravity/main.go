@@ -5,26 +5,57 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"maps"
 	"math"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/Hakuto4838/SkipList.git/datastream"
 	"github.com/Hakuto4838/SkipList.git/skiplist"
-	"github.com/Hakuto4838/SkipList.git/skiplist/gravity"
+	"github.com/Hakuto4838/SkipList.git/skiplist/registry"
+
+	// 以空白匯入登錄 -impl 可選的實作
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/Tlist"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/basic"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/falldown"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/gravity"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/la"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/lockfree"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/rebuildsl"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/sharded"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/splay"
 )
 
+// withGrid 回傳在 cfg 之上把 impl 的兩個搜尋參數設為 z 與 threshold 的 Config
+func withGrid(cfg registry.Config, impl, zParam, thParam string, z, threshold float64) registry.Config {
+	out := cfg.Clone()
+	for _, s := range []string{
+		fmt.Sprintf("%s.%s=%g", impl, zParam, z),
+		fmt.Sprintf("%s.%s=%g", impl, thParam, threshold),
+	} {
+		if err := out.Set(s); err != nil {
+			log.Fatalf("搜尋參數無效: %v", err)
+		}
+	}
+	return out
+}
+
 // evaluateCost 評估給定參數的執行時間成本
-// 返回所有 benchmark 文件在所有運行中的總時間（取平均後的總和）
-func evaluateCost(z, threshold float64, benchFiles []*datastream.BenchFile, runs int) float64 {
+// 返回所有 benchmark 文件在所有運行中的總時間（取平均後的總和），參數無法建立實作時回傳錯誤
+func evaluateCost(cfg registry.Config, impl string, benchFiles []*datastream.BenchFile, runs int) (float64, error) {
 	var totalMs float64
 
 	for _, bf := range benchFiles {
 		var fileMs float64
 		// 對每個檔案運行 runs 次並取平均
 		for i := 0; i < runs; i++ {
-			gl := gravity.NewGravityListWithParams(z, threshold)
+			gl, err := cfg.New(impl, registry.Env{Dist: bf.Dist})
+			if err != nil {
+				return 0, err
+			}
 
 			start := time.Now()
 			for _, op := range bf.Ops {
@@ -45,7 +76,7 @@ func evaluateCost(z, threshold float64, benchFiles []*datastream.BenchFile, runs
 	}
 
 	// 返回所有檔案的總時間（每個檔案取平均後加總）
-	return totalMs
+	return totalMs, nil
 }
 
 func main() {
@@ -57,20 +88,37 @@ func main() {
 	var runs int
 	var outputCSV string
 	var outputFormat string
+	var impl string
+	var zParam, thParam string
+	cfg := registry.Config{}
 
 	flag.StringVar(&benchPath, "bench", "", "單一 benchmark 檔案路徑")
 	flag.StringVar(&benchDir, "benchdir", "", "包含多個 benchmark 檔案的目錄 (使用所有 .bin 檔案)")
-	flag.Float64Var(&zMin, "zmin", 0.5, "z 的最小值")
-	flag.Float64Var(&zMax, "zmax", 3.0, "z 的最大值")
-	flag.Float64Var(&zStep, "zstep", 0.25, "z 的步長")
-	flag.Float64Var(&thMin, "thmin", 0.1, "tryThreshold 的最小值")
-	flag.Float64Var(&thMax, "thmax", 0.9, "tryThreshold 的最大值")
-	flag.Float64Var(&thStep, "thstep", 0.08, "tryThreshold 的步長")
+	flag.StringVar(&impl, "impl", "gravity", "要搜索的實作")
+	flag.StringVar(&zParam, "zparam", "z", "第一個搜索參數（對應 -zmin/-zmax/-zstep）")
+	flag.StringVar(&thParam, "thparam", "threshold", "第二個搜索參數（對應 -thmin/-thmax/-thstep）")
+	flag.Var(cfg, "param", "其他固定的實作參數 impl.key=value，可重複指定")
+	flag.Float64Var(&zMin, "zmin", 1.25, "-zparam 的最小值（gravity 的 z 需大於 1）")
+	flag.Float64Var(&zMax, "zmax", 3.0, "-zparam 的最大值")
+	flag.Float64Var(&zStep, "zstep", 0.25, "-zparam 的步長")
+	flag.Float64Var(&thMin, "thmin", 0.1, "-thparam 的最小值")
+	flag.Float64Var(&thMax, "thmax", 0.9, "-thparam 的最大值")
+	flag.Float64Var(&thStep, "thstep", 0.08, "-thparam 的步長")
 	flag.IntVar(&runs, "runs", 3, "每組參數運行的次數（取平均值）")
 	flag.StringVar(&outputCSV, "csv", "", "輸出 CSV 檔案路徑（選填，用於生成熱力圖）")
 	flag.StringVar(&outputFormat, "format", "text", "輸出格式: text 或 csv-only")
+	flag.Usage = func() {
+		flag.PrintDefaults()
+		registry.PrintUsage(flag.CommandLine.Output())
+	}
 	flag.Parse()
 
+	if _, ok := registry.Lookup(impl); !ok {
+		log.Fatalf("未知的實作: %s", impl)
+	}
+	// 以範圍起點檢查搜索參數是否存在且為數值
+	withGrid(cfg, impl, zParam, thParam, zMin, thMin)
+
 	// 收集 benchmark 檔案
 	var benchFiles []string
 
@@ -96,7 +144,7 @@ func main() {
 	totalOps := 0
 
 	if outputFormat != "csv-only" {
-		fmt.Printf("=== Gravity SkipList 網格搜索參數優化 ===\n\n")
+		fmt.Printf("=== %s 網格搜索參數優化 ===\n\n", impl)
 		fmt.Printf("載入 %d 個 benchmark 檔案...\n", len(benchFiles))
 	}
 
@@ -115,8 +163,8 @@ func main() {
 	if outputFormat != "csv-only" {
 		fmt.Printf("\n總計: %d 檔案, %d 操作\n", len(loadedBenchmarks), totalOps)
 		fmt.Printf("\n搜索範圍:\n")
-		fmt.Printf("  z: [%.2f, %.2f], 步長: %.3f\n", zMin, zMax, zStep)
-		fmt.Printf("  tryThreshold: [%.2f, %.2f], 步長: %.3f\n", thMin, thMax, thStep)
+		fmt.Printf("  %s: [%.2f, %.2f], 步長: %.3f\n", zParam, zMin, zMax, zStep)
+		fmt.Printf("  %s: [%.2f, %.2f], 步長: %.3f\n", thParam, thMin, thMax, thStep)
 		fmt.Printf("  每組運行次數: %d\n", runs)
 	}
 
@@ -146,7 +194,7 @@ func main() {
 		defer csvWriter.Flush()
 
 		// 寫入標頭
-		csvWriter.Write([]string{"z", "tryThreshold", "cost_ms"})
+		csvWriter.Write([]string{zParam, thParam, "cost_ms"})
 	}
 
 	// 執行網格搜索
@@ -154,13 +202,25 @@ func main() {
 	bestThreshold := thMin
 	bestCost := math.Inf(1)
 	testCount := 0
+	skipped := 0
 
 	startTime := time.Now()
 
 	for z := zMin; z <= zMax+0.0001; z += zStep { // 加小量避免浮點誤差
 		for th := thMin; th <= thMax+0.0001; th += thStep {
 			testCount++
-			cost := evaluateCost(z, th, loadedBenchmarks, runs)
+			cost, err := evaluateCost(withGrid(cfg, impl, zParam, thParam, z, th), impl, loadedBenchmarks, runs)
+			if err != nil {
+				// 參數無效的格點略過，不寫入 CSV
+				skipped++
+				if outputFormat != "csv-only" {
+					progress := float64(testCount) / float64(totalTests) * 100.0
+					fmt.Printf("[%6.2f%%] %s=%.4f, %s=%.4f → 略過: %v\n", progress, zParam, z, thParam, th, err)
+				} else {
+					log.Printf("略過 %s=%g, %s=%g: %v", zParam, z, thParam, th, err)
+				}
+				continue
+			}
 
 			// CSV 輸出
 			if csvWriter != nil {
@@ -174,7 +234,7 @@ func main() {
 			// 文字輸出
 			if outputFormat != "csv-only" {
 				progress := float64(testCount) / float64(totalTests) * 100.0
-				fmt.Printf("[%6.2f%%] z=%.4f, th=%.4f → %7.3f ms", progress, z, th, cost)
+				fmt.Printf("[%6.2f%%] %s=%.4f, %s=%.4f → %7.3f ms", progress, zParam, z, thParam, th, cost)
 
 				if cost < bestCost {
 					fmt.Printf(" ✓ 新最佳!")
@@ -195,13 +255,19 @@ func main() {
 	}
 
 	elapsed := time.Since(startTime)
+	if skipped == testCount {
+		log.Fatalf("所有 %d 組參數都無效，請調整搜索範圍", testCount)
+	}
 
 	if outputFormat != "csv-only" {
 		fmt.Printf("\n=== 搜索完成 ===\n")
 		fmt.Printf("總耗時: %.2f 分鐘 (%.1f 秒)\n", elapsed.Minutes(), elapsed.Seconds())
+		if skipped > 0 {
+			fmt.Printf("略過 %d 組無效參數\n", skipped)
+		}
 		fmt.Printf("\n最佳參數（對所有 benchmark 綜合表現最佳）:\n")
-		fmt.Printf("  z            = %.6f\n", bestZ)
-		fmt.Printf("  tryThreshold = %.6f\n", bestThreshold)
+		fmt.Printf("  %-12s = %.6f\n", zParam, bestZ)
+		fmt.Printf("  %-12s = %.6f\n", thParam, bestThreshold)
 		if len(loadedBenchmarks) > 1 {
 			fmt.Printf("  總成本       = %.3f ms (所有 %d 個 benchmark 的總和)\n", bestCost, len(loadedBenchmarks))
 			fmt.Printf("  平均成本     = %.3f ms (每個 benchmark)\n", bestCost/float64(len(loadedBenchmarks)))
@@ -210,24 +276,27 @@ func main() {
 		}
 
 		// 與預設參數比較
-		fmt.Printf("\n與預設參數 (z=1.8, tryThreshold=0.5) 比較:\n")
-		defaultCost := evaluateCost(1.8, 0.5, loadedBenchmarks, runs)
-		if len(loadedBenchmarks) > 1 {
-			fmt.Printf("  預設總成本: %.3f ms (所有 %d 個 benchmark)\n", defaultCost, len(loadedBenchmarks))
-		} else {
-			fmt.Printf("  預設成本: %.3f ms\n", defaultCost)
-		}
-		improvement := (defaultCost - bestCost) / defaultCost * 100.0
-		if improvement > 0 {
-			fmt.Printf("  改善: %.2f%% ✓\n", improvement)
+		fmt.Printf("\n與預設參數 (%s) 比較:\n", formatParams(cfg.Describe(impl)))
+		defaultCost, err := evaluateCost(cfg, impl, loadedBenchmarks, runs)
+		if err != nil {
+			fmt.Printf("  無法以預設參數建立 %s: %v\n", impl, err)
 		} else {
-			fmt.Printf("  變化: %.2f%%\n", improvement)
+			if len(loadedBenchmarks) > 1 {
+				fmt.Printf("  預設總成本: %.3f ms (所有 %d 個 benchmark)\n", defaultCost, len(loadedBenchmarks))
+			} else {
+				fmt.Printf("  預設成本: %.3f ms\n", defaultCost)
+			}
+			improvement := (defaultCost - bestCost) / defaultCost * 100.0
+			if improvement > 0 {
+				fmt.Printf("  改善: %.2f%% ✓\n", improvement)
+			} else {
+				fmt.Printf("  變化: %.2f%%\n", improvement)
+			}
 		}
 
 		// 使用建議
 		fmt.Printf("\n使用方式:\n")
-		fmt.Printf("  在您的程式碼中使用:\n")
-		fmt.Printf("    gl := gravity.NewGravityListWithParams(%.6f, %.6f)\n", bestZ, bestThreshold)
+		fmt.Printf("  -impl %s -param %s.%s=%g -param %s.%s=%g\n", impl, impl, zParam, bestZ, impl, thParam, bestThreshold)
 
 		if outputCSV != "" {
 			fmt.Printf("\nCSV 結果已保存至: %s\n", outputCSV)
@@ -236,3 +305,11 @@ func main() {
 	}
 }
 
+// formatParams 以 key=value, key=value 依 key 排序輸出
+func formatParams(params map[string]string) string {
+	parts := make([]string, 0, len(params))
+	for _, k := range slices.Sorted(maps.Keys(params)) {
+		parts = append(parts, k+"="+params[k])
+	}
+	return strings.Join(parts, ", ")
+}
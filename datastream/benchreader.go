@@ -0,0 +1,220 @@
+package datastream
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+)
+
+const (
+	benchHeaderSize = 8 + 2 + 2 // magic + version + reserved
+	distRecordSize  = 8 + 8     // int64 key + float64 weight
+	opRecordSize    = 1 + 8     // uint8 type + int64 key
+
+	// DefaultBatchSize 為 BenchReader 預先配置的批次大小，NextBatch 不超過此數量時不會配置記憶體
+	DefaultBatchSize = 4096
+
+	readerBufferSize = 64 << 10
+)
+
+// BenchReader 以緩衝區逐批解碼 bench 檔的操作，不會把所有操作讀進記憶體。
+// 分布表在建立時即讀入；讀取錯誤由 Err 回傳，用法與 bufio.Scanner 相同
+type BenchReader struct {
+	r       *bufio.Reader
+	in      *crcReader // 經過 r 讀取檔頭與操作，trailer 之前的位元組都計入 CRC
+	closer  io.Closer
+	version uint16
+	meta    Metadata
+	dist    map[skiplist.K]float64
+	opsOff  int64  // 操作區段的位元組偏移
+	total   uint64 // 檔頭記錄的操作數
+	read    uint64 // 已解碼的操作數
+	done    bool   // 已檢查過操作之後的 trailer
+	ops     []BenchOp
+	raw     []byte
+	err     error
+}
+
+// OpenBenchReader 開啟 filename 並讀入檔頭與分布表，使用完需呼叫 Close
+func OpenBenchReader(filename string) (*BenchReader, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	br, err := NewBenchReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	br.closer = f
+	return br, nil
+}
+
+// NewBenchReader 由 r 讀入檔頭與分布表，操作留待 Next 或 NextBatch 時才解碼
+func NewBenchReader(r io.Reader) (*BenchReader, error) {
+	br := &BenchReader{
+		r:   bufio.NewReaderSize(r, readerBufferSize),
+		ops: make([]BenchOp, DefaultBatchSize),
+		raw: make([]byte, DefaultBatchSize*opRecordSize),
+	}
+	br.in = &crcReader{r: br.r}
+	if err := br.readHeader(); err != nil {
+		return nil, err
+	}
+	return br, nil
+}
+
+func (br *BenchReader) readHeader() error {
+	var hdr [benchHeaderSize]byte
+	if _, err := io.ReadFull(br.in, hdr[:]); err != nil {
+		return formatError(br.in.n, -1, err)
+	}
+	ver, err := checkBenchHeader(hdr[:])
+	if err != nil {
+		return err
+	}
+	br.version = ver
+	if ver >= 2 {
+		if br.meta, err = readMetadata(br.in); err != nil {
+			return formatError(br.in.n, -1, err)
+		}
+	}
+
+	var cnt [8]byte
+	if _, err := io.ReadFull(br.in, cnt[:4]); err != nil {
+		return formatError(br.in.n, -1, err)
+	}
+	distCount := binary.LittleEndian.Uint32(cnt[:4])
+	br.dist = make(map[skiplist.K]float64, min(distCount, 1<<20))
+	var rec [distRecordSize]byte
+	for i := uint32(0); i < distCount; i++ {
+		if _, err := io.ReadFull(br.in, rec[:]); err != nil {
+			return formatError(br.in.n, -1, err)
+		}
+		key := int64(binary.LittleEndian.Uint64(rec[:8]))
+		br.dist[skiplist.K(key)] = math.Float64frombits(binary.LittleEndian.Uint64(rec[8:]))
+	}
+
+	if _, err := io.ReadFull(br.in, cnt[:]); err != nil {
+		return formatError(br.in.n, -1, err)
+	}
+	br.total = binary.LittleEndian.Uint64(cnt[:])
+	br.opsOff = br.in.n
+	return nil
+}
+
+// finish 在讀完所有操作後檢查 trailer（version 3 起）與檔案是否就此結束
+func (br *BenchReader) finish() error {
+	if br.version >= 3 {
+		var trailer [trailerSize]byte
+		n, err := io.ReadFull(br.r, trailer[:])
+		if err != nil {
+			return formatError(br.in.n+int64(n), -1, err)
+		}
+		if err := checkTrailer(trailer[:], br.in.n, br.total, br.in.crc); err != nil {
+			return err
+		}
+	}
+	if _, err := br.r.ReadByte(); err != io.EOF {
+		if err != nil {
+			return err
+		}
+		end := br.in.n
+		if br.version >= 3 {
+			end += trailerSize
+		}
+		return &FormatError{Offset: end, Op: -1, Err: fmt.Errorf("%w: unexpected data after the last op", ErrBadTrailer)}
+	}
+	return nil
+}
+
+// Meta 回傳檔案中的中繼資料，version 1 的檔案為 nil
+func (br *BenchReader) Meta() Metadata {
+	return br.meta
+}
+
+// Dist 回傳檔案中的分布表
+func (br *BenchReader) Dist() map[skiplist.K]float64 {
+	return br.dist
+}
+
+// Len 回傳檔頭記錄的操作總數
+func (br *BenchReader) Len() int {
+	return int(br.total)
+}
+
+// Remaining 回傳尚未讀取的操作數
+func (br *BenchReader) Remaining() int {
+	return int(br.total - br.read)
+}
+
+// Next 回傳下一筆操作，讀完或發生錯誤時回傳 false
+func (br *BenchReader) Next() (BenchOp, bool) {
+	batch := br.NextBatch(1)
+	if len(batch) == 0 {
+		return BenchOp{}, false
+	}
+	return batch[0], true
+}
+
+// NextBatch 解碼接下來最多 n 筆操作，讀完或發生錯誤時回傳 nil。
+// 回傳的切片指向內部緩衝區，下一次呼叫 Next 或 NextBatch 後即失效
+func (br *BenchReader) NextBatch(n int) []BenchOp {
+	if br.err != nil || n <= 0 {
+		return nil
+	}
+	if br.read >= br.total {
+		if !br.done {
+			br.done = true
+			br.err = br.finish()
+		}
+		return nil
+	}
+	if rem := br.total - br.read; uint64(n) > rem {
+		n = int(rem)
+	}
+	if n > len(br.ops) {
+		br.ops = make([]BenchOp, n)
+		br.raw = make([]byte, n*opRecordSize)
+	}
+
+	raw := br.raw[:n*opRecordSize]
+	if m, err := io.ReadFull(br.in, raw); err != nil {
+		br.err = formatError(br.in.n, int(br.read)+m/opRecordSize, err)
+		return nil
+	}
+	if err := checkOps(raw, int(br.read), br.opsOff+int64(br.read)*opRecordSize); err != nil {
+		br.err = err
+		return nil
+	}
+	ops := br.ops[:n]
+	for i := range ops {
+		rec := raw[i*opRecordSize : (i+1)*opRecordSize]
+		ops[i] = BenchOp{
+			Type: OperationType(rec[0]),
+			Key:  skiplist.K(int64(binary.LittleEndian.Uint64(rec[1:]))),
+		}
+	}
+	br.read += uint64(n)
+	return ops
+}
+
+// Err 回傳讀取操作時遇到的第一個錯誤，格式錯誤為 *FormatError。
+// 檔案在操作數不足時結束回傳 ErrTruncated（同時符合 io.ErrUnexpectedEOF）；
+// trailer 與 CRC32C 在讀完最後一筆之後的下一次 NextBatch 才檢查
+func (br *BenchReader) Err() error {
+	return br.err
+}
+
+// Close 關閉由 OpenBenchReader 開啟的檔案
+func (br *BenchReader) Close() error {
+	if br.closer == nil {
+		return nil
+	}
+	return br.closer.Close()
+}
@@ -0,0 +1,160 @@
+package datastream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+)
+
+// BenchView 為 bench 檔的唯讀檢視，可隨機存取第 i 筆操作與分布表。
+// Linux 上以 mmap 對映整個檔案並在開啟時預先載入分頁，操作在存取時才自對映的位元組解碼，
+// 重複重播同一個檔案不需再讀檔；其他平台則一次讀入記憶體
+type BenchView struct {
+	data    []byte
+	meta    Metadata
+	dist    []byte // 分布表區段
+	ops     []byte // 操作區段
+	distMap map[skiplist.K]float64
+	release func([]byte) error
+}
+
+// OpenBenchView 開啟 filename 並檢查格式（同 Verify），錯誤為 *FormatError。使用完需呼叫 Close，
+// Close 之後不可再呼叫 Op 與 DistAt，已取得的 Dist 與 Meta 仍可使用
+func OpenBenchView(filename string) (*BenchView, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	data, release, err := mapFile(f, fi.Size())
+	if err != nil {
+		return nil, err
+	}
+	v := &BenchView{data: data, release: release}
+	if err := v.parse(); err != nil {
+		v.Close()
+		return nil, err
+	}
+	return v, nil
+}
+
+func (v *BenchView) parse() error {
+	data := v.data
+	end := int64(len(data))
+	if len(data) < benchHeaderSize {
+		return formatError(end, -1, io.ErrUnexpectedEOF)
+	}
+	ver, err := checkBenchHeader(data)
+	if err != nil {
+		return err
+	}
+	off := benchHeaderSize
+	if ver >= 2 {
+		r := bytes.NewReader(data[off:])
+		if v.meta, err = readMetadata(r); err != nil {
+			return formatError(end-int64(r.Len()), -1, err)
+		}
+		off = len(data) - r.Len()
+	}
+
+	if len(data)-off < 4 {
+		return formatError(end, -1, io.ErrUnexpectedEOF)
+	}
+	distCount := uint64(binary.LittleEndian.Uint32(data[off:]))
+	off += 4
+
+	if uint64(len(data)-off) < distCount*distRecordSize+8 {
+		return formatError(end, -1, io.ErrUnexpectedEOF)
+	}
+	v.dist = data[off : off+int(distCount)*distRecordSize]
+	off += len(v.dist)
+	opCount := binary.LittleEndian.Uint64(data[off:])
+	off += 8
+
+	if avail := uint64(len(data)-off) / opRecordSize; opCount > avail {
+		return formatError(end, int(avail), io.ErrUnexpectedEOF)
+	}
+	v.ops = data[off : off+int(opCount)*opRecordSize]
+	if err := checkOps(v.ops, 0, int64(off)); err != nil {
+		return err
+	}
+	off += len(v.ops)
+
+	if ver >= 3 {
+		if len(data)-off < trailerSize {
+			return formatError(end, -1, io.ErrUnexpectedEOF)
+		}
+		crc := crc32.Checksum(data[:off], castagnoli)
+		if err := checkTrailer(data[off:off+trailerSize], int64(off), opCount, crc); err != nil {
+			return err
+		}
+		off += trailerSize
+	}
+	if off != len(data) {
+		return &FormatError{Offset: int64(off), Op: -1, Err: fmt.Errorf("%w: unexpected data after the last op", ErrBadTrailer)}
+	}
+	return nil
+}
+
+// Len 回傳操作總數
+func (v *BenchView) Len() int {
+	return len(v.ops) / opRecordSize
+}
+
+// Op 回傳第 i 筆操作，i 超出範圍時 panic
+func (v *BenchView) Op(i int) BenchOp {
+	rec := v.ops[i*opRecordSize : (i+1)*opRecordSize]
+	return BenchOp{
+		Type: OperationType(rec[0]),
+		Key:  skiplist.K(int64(binary.LittleEndian.Uint64(rec[1:]))),
+	}
+}
+
+// Meta 回傳檔案中的中繼資料，version 1 的檔案為 nil
+func (v *BenchView) Meta() Metadata {
+	return v.meta
+}
+
+// DistLen 回傳分布表的筆數
+func (v *BenchView) DistLen() int {
+	return len(v.dist) / distRecordSize
+}
+
+// DistAt 回傳分布表的第 i 筆，依寫入時的順序（key 升冪）
+func (v *BenchView) DistAt(i int) (skiplist.K, float64) {
+	rec := v.dist[i*distRecordSize : (i+1)*distRecordSize]
+	key := int64(binary.LittleEndian.Uint64(rec))
+	return skiplist.K(key), math.Float64frombits(binary.LittleEndian.Uint64(rec[8:]))
+}
+
+// Dist 回傳分布表的 map，第一次呼叫時建立並快取
+func (v *BenchView) Dist() map[skiplist.K]float64 {
+	if v.distMap == nil {
+		v.distMap = make(map[skiplist.K]float64, v.DistLen())
+		for i := range v.DistLen() {
+			k, w := v.DistAt(i)
+			v.distMap[k] = w
+		}
+	}
+	return v.distMap
+}
+
+// Close 解除對映
+func (v *BenchView) Close() error {
+	if v.release == nil {
+		return nil
+	}
+	err := v.release(v.data)
+	v.data, v.dist, v.ops, v.release = nil, nil, nil, nil
+	return err
+}
@@ -0,0 +1,21 @@
+//go:build linux
+
+package datastream
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile 以唯讀 mmap 對映整個檔案，MAP_POPULATE 讓分頁在開啟時就載入，
+// 之後存取不會因缺頁而在計時區間內讀檔
+func mapFile(f *os.File, size int64) ([]byte, func([]byte) error, error) {
+	if size == 0 {
+		return nil, nil, nil
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED|syscall.MAP_POPULATE)
+	if err != nil {
+		return nil, nil, &os.PathError{Op: "mmap", Path: f.Name(), Err: err}
+	}
+	return data, syscall.Munmap, nil
+}
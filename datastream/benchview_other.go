@@ -0,0 +1,17 @@
+//go:build !linux
+
+package datastream
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile 在沒有使用 mmap 的平台上把整個檔案讀入記憶體
+func mapFile(f *os.File, size int64) ([]byte, func([]byte) error, error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, nil, err
+	}
+	return data, nil, nil
+}
@@ -17,8 +17,14 @@ import (
 
 // 檔案格式（LittleEndian）：
 // [8]byte  Magic: "SLBENCH1"
-// uint16   Version: 1
+// uint16   Version: 3（仍可讀取 1、2）
 // uint16   Reserved: 0
+// 中繼資料（Version 2 起）：
+// uint32   MetaCount
+// 重複 MetaCount 次：
+//   uint8   Kind (0=string,1=int64,2=float64,3=bool)
+//   uint16  KeyLen，接著 KeyLen bytes 的 key
+//   值：string 為 uint16 長度加內容，int64、float64 為 8 bytes，bool 為 1 byte
 // uint32   DistCount
 // 重複 DistCount 次：
 //   int64   Key
@@ -27,18 +33,48 @@ import (
 // 重複 OpCount 次：
 //   uint8   OperationType (0=Query,1=Insert,2=Delete)
 //   int64   Key
+// Trailer（Version 3 起）：
+// [4]byte  Magic: "SLBT"
+// uint64   OpCount（需與前面相同）
+// uint32   Trailer 之前所有位元組的 CRC32C
 
 var (
 	benchMagic   = [8]byte{'S', 'L', 'B', 'E', 'N', 'C', 'H', '1'}
-	benchVersion = uint16(1)
+	benchVersion = uint16(3)
 )
 
+// writeBenchHeader 寫出目前版本的檔頭與中繼資料
+func writeBenchHeader(w io.Writer, meta Metadata) error {
+	buf := make([]byte, benchHeaderSize, 256)
+	copy(buf, benchMagic[:])
+	binary.LittleEndian.PutUint16(buf[8:], benchVersion)
+	buf, err := appendMetadata(buf, meta)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf)
+	return err
+}
+
+// checkBenchHeader 檢查檔頭的 magic 與版本，回傳版本
+func checkBenchHeader(hdr []byte) (uint16, error) {
+	if [8]byte(hdr[:8]) != benchMagic {
+		return 0, &FormatError{Offset: 0, Op: -1, Err: fmt.Errorf("%w: %q", ErrBadMagic, hdr[:8])}
+	}
+	ver := binary.LittleEndian.Uint16(hdr[8:])
+	if ver < 1 || ver > benchVersion {
+		return 0, &FormatError{Offset: 8, Op: -1, Err: fmt.Errorf("%w: %d", ErrBadVersion, ver)}
+	}
+	return ver, nil
+}
+
 type BenchOp struct {
 	Type OperationType
 	Key  skiplist.K
 }
 
 type BenchFile struct {
+	Meta Metadata // version 1 的檔案為 nil
 	Dist map[skiplist.K]float64
 	Ops  []BenchOp
 }
@@ -66,15 +102,18 @@ func WriteBenchFileFromZipf(gen *ZipfDataGenerator, k int, filename string) erro
 		return err
 	}
 	defer file.Close()
+	out := newBenchWriter(file)
 
 	// Header
-	if _, err := file.Write(benchMagic[:]); err != nil {
-		return err
-	}
-	if err := binary.Write(file, binary.LittleEndian, benchVersion); err != nil {
-		return err
-	}
-	if err := binary.Write(file, binary.LittleEndian, uint16(0)); err != nil { // reserved
+	meta := Metadata{
+		{"generator", "zipf-v1"},
+		{"n", int64(gen.n)},
+		{"a", gen.a},
+		{"b", gen.b},
+		{"seed", gen.seed},
+		{"k", int64(k)},
+	}
+	if err := writeBenchHeader(out, meta); err != nil {
 		return err
 	}
 
@@ -86,22 +125,22 @@ func WriteBenchFileFromZipf(gen *ZipfDataGenerator, k int, filename string) erro
 	}
 	sort.Ints(keys)
 
-	if err := binary.Write(file, binary.LittleEndian, uint32(len(keys))); err != nil {
+	if err := binary.Write(out, binary.LittleEndian, uint32(len(keys))); err != nil {
 		return err
 	}
 	for _, ik := range keys {
 		k64 := int64(ik)
 		w := dist[skiplist.K(ik)]
-		if err := binary.Write(file, binary.LittleEndian, k64); err != nil {
+		if err := binary.Write(out, binary.LittleEndian, k64); err != nil {
 			return err
 		}
-		if err := binary.Write(file, binary.LittleEndian, w); err != nil {
+		if err := binary.Write(out, binary.LittleEndian, w); err != nil {
 			return err
 		}
 	}
 
 	// Operations
-	if err := binary.Write(file, binary.LittleEndian, uint64(k)); err != nil {
+	if err := binary.Write(out, binary.LittleEndian, uint64(k)); err != nil {
 		return err
 	}
 
@@ -125,15 +164,18 @@ func WriteBenchFileFromZipf(gen *ZipfDataGenerator, k int, filename string) erro
 			}
 		}
 
-		if err := binary.Write(file, binary.LittleEndian, uint8(op)); err != nil {
+		if err := binary.Write(out, binary.LittleEndian, uint8(op)); err != nil {
 			return err
 		}
-		if err := binary.Write(file, binary.LittleEndian, int64(idx)); err != nil {
+		if err := binary.Write(out, binary.LittleEndian, int64(idx)); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	if err := out.finish(uint64(k)); err != nil {
+		return err
+	}
+	return file.Close()
 }
 
 // writeBenchFileFromUniform 使用均勻分布產生操作序列並寫入檔案。
@@ -162,15 +204,19 @@ func writeBenchFileFromUniform(n int, seed uint64, k int, phase1Ratio, deleteRat
 		return nil, err
 	}
 	defer file.Close()
+	out := newBenchWriter(file)
 
 	// Header
-	if _, err := file.Write(benchMagic[:]); err != nil {
-		return nil, err
-	}
-	if err := binary.Write(file, binary.LittleEndian, benchVersion); err != nil {
-		return nil, err
-	}
-	if err := binary.Write(file, binary.LittleEndian, uint16(0)); err != nil { // reserved
+	meta := Metadata{
+		{"generator", "uniform"},
+		{"n", int64(n)},
+		{"seed", int64(seed)},
+		{"k", int64(k)},
+		{"phase1Ratio", phase1Ratio},
+		{"deleteRatio", deleteRatio},
+		{"simpleKey", simpleKey},
+	}
+	if err := writeBenchHeader(out, meta); err != nil {
 		return nil, err
 	}
 
@@ -198,7 +244,7 @@ func writeBenchFileFromUniform(n int, seed uint64, k int, phase1Ratio, deleteRat
 	weight := 1.0 / float64(n)
 
 	// 3) 將 (key, weight) 對應後，依 key 升冪輸出
-	if err := binary.Write(file, binary.LittleEndian, uint32(n)); err != nil {
+	if err := binary.Write(out, binary.LittleEndian, uint32(n)); err != nil {
 		return nil, err
 	}
 	type kv struct {
@@ -211,10 +257,10 @@ func writeBenchFileFromUniform(n int, seed uint64, k int, phase1Ratio, deleteRat
 	}
 	sort.Slice(pairs, func(i, j int) bool { return pairs[i].k < pairs[j].k })
 	for _, p := range pairs {
-		if err := binary.Write(file, binary.LittleEndian, int64(p.k)); err != nil {
+		if err := binary.Write(out, binary.LittleEndian, int64(p.k)); err != nil {
 			return nil, err
 		}
-		if err := binary.Write(file, binary.LittleEndian, p.w); err != nil {
+		if err := binary.Write(out, binary.LittleEndian, p.w); err != nil {
 			return nil, err
 		}
 	}
@@ -226,7 +272,7 @@ func writeBenchFileFromUniform(n int, seed uint64, k int, phase1Ratio, deleteRat
 	}
 
 	// Operations count
-	if err := binary.Write(file, binary.LittleEndian, uint64(k)); err != nil {
+	if err := binary.Write(out, binary.LittleEndian, uint64(k)); err != nil {
 		return nil, err
 	}
 
@@ -259,10 +305,10 @@ func writeBenchFileFromUniform(n int, seed uint64, k int, phase1Ratio, deleteRat
 				op = OpQuery
 			}
 		}
-		if err := binary.Write(file, binary.LittleEndian, uint8(op)); err != nil {
+		if err := binary.Write(out, binary.LittleEndian, uint8(op)); err != nil {
 			return nil, err
 		}
-		if err := binary.Write(file, binary.LittleEndian, int64(key)); err != nil {
+		if err := binary.Write(out, binary.LittleEndian, int64(key)); err != nil {
 			return nil, err
 		}
 	}
@@ -283,14 +329,21 @@ func writeBenchFileFromUniform(n int, seed uint64, k int, phase1Ratio, deleteRat
 				op = OpQuery
 			}
 		}
-		if err := binary.Write(file, binary.LittleEndian, uint8(op)); err != nil {
+		if err := binary.Write(out, binary.LittleEndian, uint8(op)); err != nil {
 			return nil, err
 		}
-		if err := binary.Write(file, binary.LittleEndian, int64(key)); err != nil {
+		if err := binary.Write(out, binary.LittleEndian, int64(key)); err != nil {
 			return nil, err
 		}
 	}
 
+	if err := out.finish(uint64(k)); err != nil {
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+
 	info.Entropy = EntropyFromDist(distOut)
 	info.Dist = distOut
 
@@ -341,15 +394,21 @@ func WriteBenchFileFromZipfV2(n int, s, v float64, seed uint64, k int, phase1Rat
 		return nil, err
 	}
 	defer file.Close()
+	out := newBenchWriter(file)
 
 	// Header
-	if _, err := file.Write(benchMagic[:]); err != nil {
-		return nil, err
-	}
-	if err := binary.Write(file, binary.LittleEndian, benchVersion); err != nil {
-		return nil, err
-	}
-	if err := binary.Write(file, binary.LittleEndian, uint16(0)); err != nil { // reserved
+	meta := Metadata{
+		{"generator", "zipf-v2"},
+		{"n", int64(n)},
+		{"s", s},
+		{"v", v},
+		{"seed", int64(seed)},
+		{"k", int64(k)},
+		{"phase1Ratio", phase1Ratio},
+		{"deleteRatio", deleteRatio},
+		{"simpleKey", simpleKey},
+	}
+	if err := writeBenchHeader(out, meta); err != nil {
 		return nil, err
 	}
 
@@ -386,7 +445,7 @@ func WriteBenchFileFromZipfV2(n int, s, v float64, seed uint64, k int, phase1Rat
 	}
 
 	// 3) 將 (key, weight) 對應後，依 key 升冪輸出
-	if err := binary.Write(file, binary.LittleEndian, uint32(n)); err != nil {
+	if err := binary.Write(out, binary.LittleEndian, uint32(n)); err != nil {
 		return nil, err
 	}
 	type kv struct {
@@ -399,10 +458,10 @@ func WriteBenchFileFromZipfV2(n int, s, v float64, seed uint64, k int, phase1Rat
 	}
 	sort.Slice(pairs, func(i, j int) bool { return pairs[i].k < pairs[j].k })
 	for _, p := range pairs {
-		if err := binary.Write(file, binary.LittleEndian, int64(p.k)); err != nil {
+		if err := binary.Write(out, binary.LittleEndian, int64(p.k)); err != nil {
 			return nil, err
 		}
-		if err := binary.Write(file, binary.LittleEndian, p.w); err != nil {
+		if err := binary.Write(out, binary.LittleEndian, p.w); err != nil {
 			return nil, err
 		}
 	}
@@ -414,7 +473,7 @@ func WriteBenchFileFromZipfV2(n int, s, v float64, seed uint64, k int, phase1Rat
 	}
 
 	// Operations count
-	if err := binary.Write(file, binary.LittleEndian, uint64(k)); err != nil {
+	if err := binary.Write(out, binary.LittleEndian, uint64(k)); err != nil {
 		return nil, err
 	}
 
@@ -447,10 +506,10 @@ func WriteBenchFileFromZipfV2(n int, s, v float64, seed uint64, k int, phase1Rat
 				op = OpQuery
 			}
 		}
-		if err := binary.Write(file, binary.LittleEndian, uint8(op)); err != nil {
+		if err := binary.Write(out, binary.LittleEndian, uint8(op)); err != nil {
 			return nil, err
 		}
-		if err := binary.Write(file, binary.LittleEndian, int64(key)); err != nil {
+		if err := binary.Write(out, binary.LittleEndian, int64(key)); err != nil {
 			return nil, err
 		}
 	}
@@ -471,14 +530,21 @@ func WriteBenchFileFromZipfV2(n int, s, v float64, seed uint64, k int, phase1Rat
 				op = OpQuery
 			}
 		}
-		if err := binary.Write(file, binary.LittleEndian, uint8(op)); err != nil {
+		if err := binary.Write(out, binary.LittleEndian, uint8(op)); err != nil {
 			return nil, err
 		}
-		if err := binary.Write(file, binary.LittleEndian, int64(key)); err != nil {
+		if err := binary.Write(out, binary.LittleEndian, int64(key)); err != nil {
 			return nil, err
 		}
 	}
 
+	if err := out.finish(uint64(k)); err != nil {
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+
 	info.Entropy = EntropyFromDist(distOut)
 	info.Dist = distOut
 
@@ -486,70 +552,23 @@ func WriteBenchFileFromZipfV2(n int, s, v float64, seed uint64, k int, phase1Rat
 }
 
 // ReadBenchFile 讀取 bin 檔案，回傳分布與操作序列。
+// 操作會全部讀進記憶體，大型檔案請改用 OpenBenchReader 串流讀取
 func ReadBenchFile(filename string) (*BenchFile, error) {
-	fd, err := os.Open(filename)
+	br, err := OpenBenchReader(filename)
 	if err != nil {
 		return nil, err
 	}
-	defer fd.Close()
+	defer br.Close()
 
-	var magic [8]byte
-	if _, err := io.ReadFull(fd, magic[:]); err != nil {
-		return nil, err
-	}
-	if magic != benchMagic {
-		return nil, fmt.Errorf("invalid magic: %q", magic)
+	// 檔頭的操作數未經驗證，預先配置的容量設上限，不足時由 append 擴充
+	ops := make([]BenchOp, 0, min(br.total, 1<<20))
+	for batch := br.NextBatch(DefaultBatchSize); batch != nil; batch = br.NextBatch(DefaultBatchSize) {
+		ops = append(ops, batch...)
 	}
-	var ver uint16
-	if err := binary.Read(fd, binary.LittleEndian, &ver); err != nil {
+	if err := br.Err(); err != nil {
 		return nil, err
 	}
-	if ver != benchVersion {
-		return nil, fmt.Errorf("unsupported version: %d", ver)
-	}
-	// reserved
-	var reserved uint16
-	if err := binary.Read(fd, binary.LittleEndian, &reserved); err != nil {
-		return nil, err
-	}
-
-	// distribution
-	var distCount uint32
-	if err := binary.Read(fd, binary.LittleEndian, &distCount); err != nil {
-		return nil, err
-	}
-	dist := make(map[skiplist.K]float64, distCount)
-	for i := uint32(0); i < distCount; i++ {
-		var key int64
-		var weight float64
-		if err := binary.Read(fd, binary.LittleEndian, &key); err != nil {
-			return nil, err
-		}
-		if err := binary.Read(fd, binary.LittleEndian, &weight); err != nil {
-			return nil, err
-		}
-		dist[skiplist.K(key)] = weight
-	}
-
-	// operations
-	var opCount uint64
-	if err := binary.Read(fd, binary.LittleEndian, &opCount); err != nil {
-		return nil, err
-	}
-	ops := make([]BenchOp, 0, opCount)
-	for i := uint64(0); i < opCount; i++ {
-		var t uint8
-		var key int64
-		if err := binary.Read(fd, binary.LittleEndian, &t); err != nil {
-			return nil, err
-		}
-		if err := binary.Read(fd, binary.LittleEndian, &key); err != nil {
-			return nil, err
-		}
-		ops = append(ops, BenchOp{Type: OperationType(t), Key: skiplist.K(key)})
-	}
-
-	return &BenchFile{Dist: dist, Ops: ops}, nil
+	return &BenchFile{Meta: br.Meta(), Dist: br.Dist(), Ops: ops}, nil
 }
 
 // ToSequenceModel 將 BenchFile 轉為可重播的 SequenceModel（以 int key）。
@@ -1,8 +1,12 @@
 package datastream
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"math"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -198,3 +202,341 @@ func TestReadBenchFileKeysCoverage(t *testing.T) {
 		t.Errorf("expected at least %d unique keys in ops, got %d", n, len(seenKeys))
 	}
 }
+
+// BenchReader 混用 Next 與不同大小的 NextBatch，結果需與 ReadBenchFile 相同
+func TestBenchReaderMatchesReadBenchFile(t *testing.T) {
+	k := 3*DefaultBatchSize + 17
+	file := filepath.Join(t.TempDir(), "bench.bin")
+	if _, err := WriteBenchFileFromZipfV2(100, 1.2, 1.0, 7, k, 0.5, 0.1, file, false); err != nil {
+		t.Fatalf("WriteBenchFileFromZipfV2 error: %v", err)
+	}
+	bf, err := ReadBenchFile(file)
+	if err != nil {
+		t.Fatalf("ReadBenchFile error: %v", err)
+	}
+
+	br, err := OpenBenchReader(file)
+	if err != nil {
+		t.Fatalf("OpenBenchReader error: %v", err)
+	}
+	defer br.Close()
+	if br.Len() != k || len(br.Dist()) != len(bf.Dist) {
+		t.Fatalf("Len() = %d, len(Dist()) = %d", br.Len(), len(br.Dist()))
+	}
+
+	var got []BenchOp
+	for _, n := range []int{1, 5, DefaultBatchSize, 2*DefaultBatchSize + 3} {
+		if n == 1 {
+			op, ok := br.Next()
+			if !ok {
+				t.Fatal("Next returned false")
+			}
+			got = append(got, op)
+			continue
+		}
+		got = append(got, br.NextBatch(n)...)
+	}
+	for batch := br.NextBatch(100); batch != nil; batch = br.NextBatch(100) {
+		got = append(got, batch...)
+	}
+	if err := br.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	if br.Remaining() != 0 {
+		t.Fatalf("Remaining() = %d", br.Remaining())
+	}
+	if len(got) != len(bf.Ops) {
+		t.Fatalf("read %d ops, want %d", len(got), len(bf.Ops))
+	}
+	for i := range got {
+		if got[i] != bf.Ops[i] {
+			t.Fatalf("op[%d] = %v, want %v", i, got[i], bf.Ops[i])
+		}
+	}
+}
+
+func TestBenchReaderTruncated(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "bench.bin")
+	if _, err := WriteBenchFileFromZipfV2(10, 1.2, 1.0, 7, 100, 0.5, 0.1, file, false); err != nil {
+		t.Fatalf("WriteBenchFileFromZipfV2 error: %v", err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 截在最後一筆操作中間（trailer 之前）
+	br, err := NewBenchReader(bytes.NewReader(data[:len(data)-trailerSize-4]))
+	if err != nil {
+		t.Fatalf("NewBenchReader error: %v", err)
+	}
+	n := 0
+	for batch := br.NextBatch(10); batch != nil; batch = br.NextBatch(10) {
+		n += len(batch)
+	}
+	if n != 90 || !errors.Is(br.Err(), io.ErrUnexpectedEOF) {
+		t.Fatalf("read %d ops, Err() = %v", n, br.Err())
+	}
+	var fe *FormatError
+	if !errors.As(br.Err(), &fe) || !errors.Is(fe, ErrTruncated) || fe.Op != 99 || fe.Offset != int64(len(data)-trailerSize-4) {
+		t.Fatalf("Err() = %#v", br.Err())
+	}
+	if _, err := ReadBenchFile(file + ".missing"); err == nil {
+		t.Fatal("ReadBenchFile of a missing file succeeded")
+	}
+}
+
+func TestBenchViewMatchesReadBenchFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "bench.bin")
+	if _, err := WriteBenchFileFromZipfV2(100, 1.2, 1.0, 7, 1000, 0.5, 0.1, file, false); err != nil {
+		t.Fatalf("WriteBenchFileFromZipfV2 error: %v", err)
+	}
+	bf, err := ReadBenchFile(file)
+	if err != nil {
+		t.Fatalf("ReadBenchFile error: %v", err)
+	}
+
+	v, err := OpenBenchView(file)
+	if err != nil {
+		t.Fatalf("OpenBenchView error: %v", err)
+	}
+	if v.Len() != len(bf.Ops) || v.DistLen() != len(bf.Dist) {
+		t.Fatalf("Len() = %d, DistLen() = %d", v.Len(), v.DistLen())
+	}
+	for _, i := range []int{v.Len() - 1, 0, v.Len() / 2} {
+		if v.Op(i) != bf.Ops[i] {
+			t.Fatalf("Op(%d) = %v, want %v", i, v.Op(i), bf.Ops[i])
+		}
+	}
+	for i := range v.Len() {
+		if v.Op(i) != bf.Ops[i] {
+			t.Fatalf("Op(%d) = %v, want %v", i, v.Op(i), bf.Ops[i])
+		}
+	}
+	prev := skiplist.K(math.MinInt64)
+	for i := range v.DistLen() {
+		k, w := v.DistAt(i)
+		if i > 0 && k <= prev {
+			t.Fatalf("DistAt(%d) key %d not ascending after %d", i, k, prev)
+		}
+		if bf.Dist[k] != w {
+			t.Fatalf("DistAt(%d) = (%d, %v), want weight %v", i, k, w, bf.Dist[k])
+		}
+		prev = k
+	}
+	dist := v.Dist()
+	if err := v.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+	if len(dist) != len(bf.Dist) {
+		t.Fatalf("len(Dist()) = %d after Close, want %d", len(dist), len(bf.Dist))
+	}
+	if err := v.Close(); err != nil {
+		t.Fatalf("second Close error: %v", err)
+	}
+}
+
+func TestBenchViewInvalid(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "bench.bin")
+	if _, err := WriteBenchFileFromZipfV2(10, 1.2, 1.0, 7, 100, 0.5, 0.1, file, false); err != nil {
+		t.Fatalf("WriteBenchFileFromZipfV2 error: %v", err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	truncated := filepath.Join(dir, "truncated.bin")
+	if err := os.WriteFile(truncated, data[:len(data)-4], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenBenchView(truncated); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("OpenBenchView(truncated) error = %v", err)
+	}
+
+	badMagic := filepath.Join(dir, "magic.bin")
+	if err := os.WriteFile(badMagic, append([]byte("NOTBENCH"), data[8:]...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenBenchView(badMagic); err == nil {
+		t.Fatal("OpenBenchView with bad magic succeeded")
+	}
+
+	empty := filepath.Join(dir, "empty.bin")
+	if err := os.WriteFile(empty, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenBenchView(empty); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("OpenBenchView(empty) error = %v", err)
+	}
+}
+
+func TestBenchFileMetadata(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "bench.bin")
+	if _, err := WriteBenchFileFromZipfV2(10, 1.2, 1.0, 7, 100, 0.5, 0.1, file, true); err != nil {
+		t.Fatalf("WriteBenchFileFromZipfV2 error: %v", err)
+	}
+	want := Metadata{
+		{"generator", "zipf-v2"},
+		{"n", int64(10)},
+		{"s", 1.2},
+		{"v", 1.0},
+		{"seed", int64(7)},
+		{"k", int64(100)},
+		{"phase1Ratio", 0.5},
+		{"deleteRatio", 0.1},
+		{"simpleKey", true},
+	}
+	bf, err := ReadBenchFile(file)
+	if err != nil {
+		t.Fatalf("ReadBenchFile error: %v", err)
+	}
+	if bf.Meta.String() != want.String() {
+		t.Fatalf("Meta = %v, want %v", bf.Meta, want)
+	}
+	v, err := OpenBenchView(file)
+	if err != nil {
+		t.Fatalf("OpenBenchView error: %v", err)
+	}
+	defer v.Close()
+	if v.Meta().String() != want.String() || v.Len() != 100 {
+		t.Fatalf("view Meta = %v, Len() = %d", v.Meta(), v.Len())
+	}
+	if g, ok := v.Meta().Get("generator"); !ok || g != "zipf-v2" {
+		t.Fatalf("Get(generator) = %v, %v", g, ok)
+	}
+
+	// version 1 的檔案仍可讀取，沒有中繼資料
+	old, err := OpenBenchView("gravity_test.bin")
+	if err != nil {
+		t.Fatalf("OpenBenchView(v1) error: %v", err)
+	}
+	defer old.Close()
+	v1, err := ReadBenchFile("gravity_test.bin")
+	if err != nil {
+		t.Fatalf("ReadBenchFile(v1) error: %v", err)
+	}
+	if old.Meta() != nil || v1.Meta != nil || old.Len() != len(v1.Ops) {
+		t.Fatalf("v1 Meta = %v, %v, Len() = %d", old.Meta(), v1.Meta, old.Len())
+	}
+}
+
+func TestMetadataEncoding(t *testing.T) {
+	m := Metadata{
+		{"name", "uniform"},
+		{"empty", ""},
+		{"n", int64(-3)},
+		{"ratio", 0.25},
+		{"flag", false},
+	}
+	buf, err := appendMetadata(nil, m)
+	if err != nil {
+		t.Fatalf("appendMetadata error: %v", err)
+	}
+	got, err := readMetadata(bytes.NewReader(buf))
+	if err != nil {
+		t.Fatalf("readMetadata error: %v", err)
+	}
+	if len(got) != len(m) {
+		t.Fatalf("readMetadata = %v, want %v", got, m)
+	}
+	for i := range m {
+		if got[i] != m[i] {
+			t.Fatalf("field %d = %#v, want %#v", i, got[i], m[i])
+		}
+	}
+	if _, err := readMetadata(bytes.NewReader(buf[:len(buf)-1])); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("readMetadata(truncated) error = %v", err)
+	}
+	if _, err := appendMetadata(nil, Metadata{{"n", 3}}); err == nil {
+		t.Fatal("appendMetadata accepted an int value")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "bench.bin")
+	if _, err := WriteBenchFileFromZipfV2(10, 1.2, 1.0, 7, 100, 0.5, 0.1, file, false); err != nil {
+		t.Fatalf("WriteBenchFileFromZipfV2 error: %v", err)
+	}
+	if err := Verify(file); err != nil {
+		t.Fatalf("Verify error: %v", err)
+	}
+	if err := Verify("gravity_test.bin"); err != nil {
+		t.Fatalf("Verify(v1) error: %v", err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := OpenBenchView(file)
+	if err != nil {
+		t.Fatalf("OpenBenchView error: %v", err)
+	}
+	opsOff := len(data) - trailerSize - v.Len()*opRecordSize
+	v.Close()
+
+	// 每種損壞都需由 Verify、ReadBenchFile 與 OpenBenchView 回報相同的位置
+	tests := []struct {
+		name   string
+		modify func([]byte) []byte
+		want   error
+		offset int
+		op     int
+	}{
+		{"magic", func(b []byte) []byte { b[0] = 'X'; return b }, ErrBadMagic, 0, -1},
+		{"version", func(b []byte) []byte { b[8] = 9; return b }, ErrBadVersion, 8, -1},
+		{"op type", func(b []byte) []byte { b[opsOff+7*opRecordSize] = 3; return b }, ErrBadOp, opsOff + 7*opRecordSize, 7},
+		{"key", func(b []byte) []byte { b[opsOff+7*opRecordSize+1] ^= 1; return b }, ErrChecksum, len(data) - 4, -1},
+		{"op count", func(b []byte) []byte { b[len(b)-12]++; return b }, ErrBadTrailer, len(data) - 12, -1},
+		{"truncated", func(b []byte) []byte { return b[:opsOff+50*opRecordSize+3] }, ErrTruncated, opsOff + 50*opRecordSize + 3, 50},
+		{"trailer", func(b []byte) []byte { return b[:len(b)-1] }, ErrTruncated, len(data) - 1, -1},
+		{"extra", func(b []byte) []byte { return append(b, 0) }, ErrBadTrailer, len(data), -1},
+	}
+	for _, tc := range tests {
+		path := filepath.Join(dir, "bad.bin")
+		if err := os.WriteFile(path, tc.modify(bytes.Clone(data)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		for _, open := range []func(string) error{
+			Verify,
+			func(p string) error { _, err := ReadBenchFile(p); return err },
+			func(p string) error {
+				v, err := OpenBenchView(p)
+				if err == nil {
+					v.Close()
+				}
+				return err
+			},
+		} {
+			err := open(path)
+			var fe *FormatError
+			if !errors.Is(err, tc.want) || !errors.As(err, &fe) || fe.Offset != int64(tc.offset) || fe.Op != tc.op {
+				t.Fatalf("%s: error = %v, want %v at offset %d op %d", tc.name, err, tc.want, tc.offset, tc.op)
+			}
+		}
+	}
+}
+
+func TestReadBenchFileHugeOpCount(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "bench.bin")
+	if _, err := WriteBenchFileFromZipfV2(10, 1.2, 1.0, 7, 100, 0.5, 0.1, file, false); err != nil {
+		t.Fatalf("WriteBenchFileFromZipfV2 error: %v", err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 把檔頭的 OpCount 改成極大值，應回報截斷而不是配置大量記憶體
+	countOff := len(data) - trailerSize - 100*opRecordSize - 8
+	for i := range 8 {
+		data[countOff+i] = 0xff
+	}
+	data[countOff+7] = 0x7f
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadBenchFile(file); !errors.Is(err, ErrTruncated) {
+		t.Fatalf("ReadBenchFile error = %v, want ErrTruncated", err)
+	}
+}
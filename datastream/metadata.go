@@ -0,0 +1,197 @@
+package datastream
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MetaKind 為中繼資料值在檔案中的型別
+type MetaKind uint8
+
+const (
+	MetaString MetaKind = iota
+	MetaInt
+	MetaFloat
+	MetaBool
+)
+
+// MetaField 為一筆中繼資料，Value 的型別為 string、int64、float64 或 bool
+type MetaField struct {
+	Key   string
+	Value any
+}
+
+// Metadata 為 bench 檔中依寫入順序排列的中繼資料，記錄產生器名稱與其參數，
+// version 1 的檔案沒有中繼資料
+type Metadata []MetaField
+
+// Get 回傳 key 對應的值
+func (m Metadata) Get(key string) (any, bool) {
+	for _, f := range m {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Map 將中繼資料轉為 map，供 json 等輸出使用
+func (m Metadata) Map() map[string]any {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(m))
+	for _, f := range m {
+		out[f.Key] = f.Value
+	}
+	return out
+}
+
+// Format 以 key=value 依寫入順序輸出，以 sep 分隔
+func (m Metadata) Format(sep string) string {
+	parts := make([]string, len(m))
+	for i, f := range m {
+		parts[i] = f.Key + "=" + formatMetaValue(f.Value)
+	}
+	return strings.Join(parts, sep)
+}
+
+func (m Metadata) String() string {
+	return m.Format(" ")
+}
+
+func formatMetaValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(v)
+}
+
+// appendMetadata 將中繼資料編碼後附加到 buf：
+// uint32 MetaCount，接著每筆為 uint8 Kind、uint16 KeyLen + key，
+// 值為 uint16 長度 + 內容（string）、int64、float64 或 uint8（bool）
+func appendMetadata(buf []byte, m Metadata) ([]byte, error) {
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(m)))
+	for _, f := range m {
+		if len(f.Key) > math.MaxUint16 {
+			return nil, fmt.Errorf("metadata key too long: %d bytes", len(f.Key))
+		}
+		var kind MetaKind
+		switch f.Value.(type) {
+		case string:
+			kind = MetaString
+		case int64:
+			kind = MetaInt
+		case float64:
+			kind = MetaFloat
+		case bool:
+			kind = MetaBool
+		default:
+			return nil, fmt.Errorf("metadata %s: unsupported type %T", f.Key, f.Value)
+		}
+		buf = append(buf, byte(kind))
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(len(f.Key)))
+		buf = append(buf, f.Key...)
+		switch v := f.Value.(type) {
+		case string:
+			if len(v) > math.MaxUint16 {
+				return nil, fmt.Errorf("metadata %s: value too long: %d bytes", f.Key, len(v))
+			}
+			buf = binary.LittleEndian.AppendUint16(buf, uint16(len(v)))
+			buf = append(buf, v...)
+		case int64:
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(v))
+		case float64:
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(v))
+		case bool:
+			b := byte(0)
+			if v {
+				b = 1
+			}
+			buf = append(buf, b)
+		}
+	}
+	return buf, nil
+}
+
+// readMetadata 由 r 解碼 appendMetadata 寫出的中繼資料，資料不足時回傳 io.ErrUnexpectedEOF
+func readMetadata(r io.Reader) (Metadata, error) {
+	var scratch [8]byte
+	readFull := func(n int) ([]byte, error) {
+		var buf []byte
+		if n <= len(scratch) {
+			buf = scratch[:n]
+		} else {
+			buf = make([]byte, n)
+		}
+		if _, err := io.ReadFull(r, buf); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		return buf, nil
+	}
+	readString := func() (string, error) {
+		b, err := readFull(2)
+		if err != nil {
+			return "", err
+		}
+		b, err = readFull(int(binary.LittleEndian.Uint16(b)))
+		return string(b), err
+	}
+
+	b, err := readFull(4)
+	if err != nil {
+		return nil, err
+	}
+	count := binary.LittleEndian.Uint32(b)
+	m := make(Metadata, 0, min(count, 64))
+	for i := uint32(0); i < count; i++ {
+		b, err := readFull(1)
+		if err != nil {
+			return nil, err
+		}
+		kind := MetaKind(b[0])
+		key, err := readString()
+		if err != nil {
+			return nil, err
+		}
+		var v any
+		switch kind {
+		case MetaString:
+			v, err = readString()
+		case MetaInt:
+			if b, err = readFull(8); err == nil {
+				v = int64(binary.LittleEndian.Uint64(b))
+			}
+		case MetaFloat:
+			if b, err = readFull(8); err == nil {
+				v = math.Float64frombits(binary.LittleEndian.Uint64(b))
+			}
+		case MetaBool:
+			if b, err = readFull(1); err == nil {
+				v = b[0] != 0
+			}
+		default:
+			return nil, fmt.Errorf("%w: %s has unknown kind %d", ErrBadMeta, key, kind)
+		}
+		if err != nil {
+			return nil, err
+		}
+		m = append(m, MetaField{Key: key, Value: v})
+	}
+	return m, nil
+}
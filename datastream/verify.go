@@ -0,0 +1,140 @@
+package datastream
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// bench 檔的格式錯誤，實際回傳的錯誤為包裝這些值的 *FormatError，以 errors.Is 判斷
+var (
+	ErrBadMagic   = errors.New("bad magic")
+	ErrBadVersion = errors.New("unsupported version")
+	ErrBadMeta    = errors.New("bad metadata")
+	ErrBadOp      = errors.New("invalid operation type")
+	ErrBadTrailer = errors.New("bad trailer")
+	ErrChecksum   = errors.New("checksum mismatch")
+	// ErrTruncated 同時符合 io.ErrUnexpectedEOF
+	ErrTruncated = fmt.Errorf("truncated: %w", io.ErrUnexpectedEOF)
+)
+
+const (
+	trailerSize = 4 + 8 + 4 // magic + OpCount + CRC32C
+)
+
+var (
+	trailerMagic = [4]byte{'S', 'L', 'B', 'T'}
+	castagnoli   = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// FormatError 描述 bench 檔中第一個問題的位置
+type FormatError struct {
+	Offset int64 // 問題所在的位元組偏移
+	Op     int   // 問題所在的操作索引，不在操作區段時為 -1
+	Err    error
+}
+
+func (e *FormatError) Error() string {
+	if e.Op >= 0 {
+		return fmt.Sprintf("offset %d (op %d): %v", e.Offset, e.Op, e.Err)
+	}
+	return fmt.Sprintf("offset %d: %v", e.Offset, e.Err)
+}
+
+func (e *FormatError) Unwrap() error {
+	return e.Err
+}
+
+// formatError 建立 FormatError，讀取時遇到的 EOF 轉為 ErrTruncated
+func formatError(off int64, op int, err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = ErrTruncated
+	}
+	return &FormatError{Offset: off, Op: op, Err: err}
+}
+
+// checkOps 檢查 raw 中每筆操作的種類，first 為 raw 第一筆的操作索引，off 為其位元組偏移
+func checkOps(raw []byte, first int, off int64) error {
+	for i := 0; i < len(raw); i += opRecordSize {
+		if t := OperationType(raw[i]); t > OpDelete {
+			op := first + i/opRecordSize
+			return &FormatError{Offset: off + int64(i), Op: op, Err: fmt.Errorf("%w: %d", ErrBadOp, t)}
+		}
+	}
+	return nil
+}
+
+// appendTrailer 附加 version 3 起的 trailer：magic、OpCount 與之前所有位元組的 CRC32C
+func appendTrailer(buf []byte, ops uint64, crc uint32) []byte {
+	buf = append(buf, trailerMagic[:]...)
+	buf = binary.LittleEndian.AppendUint64(buf, ops)
+	return binary.LittleEndian.AppendUint32(buf, crc)
+}
+
+// checkTrailer 比對 trailer 與檔頭的操作數及計算出的 CRC32C，off 為 trailer 的位元組偏移
+func checkTrailer(trailer []byte, off int64, ops uint64, crc uint32) error {
+	if [4]byte(trailer[:4]) != trailerMagic {
+		return &FormatError{Offset: off, Op: -1, Err: fmt.Errorf("%w: magic %q", ErrBadTrailer, trailer[:4])}
+	}
+	if got := binary.LittleEndian.Uint64(trailer[4:]); got != ops {
+		return &FormatError{Offset: off + 4, Op: -1, Err: fmt.Errorf("%w: op count %d, header has %d", ErrBadTrailer, got, ops)}
+	}
+	if got := binary.LittleEndian.Uint32(trailer[12:]); got != crc {
+		return &FormatError{Offset: off + 12, Op: -1, Err: fmt.Errorf("%w: stored %08x, computed %08x", ErrChecksum, got, crc)}
+	}
+	return nil
+}
+
+// benchWriter 寫出時一併計算 CRC32C，finish 寫出 trailer
+type benchWriter struct {
+	w   *bufio.Writer
+	crc uint32
+}
+
+func newBenchWriter(w io.Writer) *benchWriter {
+	return &benchWriter{w: bufio.NewWriterSize(w, readerBufferSize)}
+}
+
+func (bw *benchWriter) Write(p []byte) (int, error) {
+	bw.crc = crc32.Update(bw.crc, castagnoli, p)
+	return bw.w.Write(p)
+}
+
+// finish 寫出 trailer 並清空緩衝區，ops 需與檔頭的 OpCount 相同
+func (bw *benchWriter) finish(ops uint64) error {
+	if _, err := bw.w.Write(appendTrailer(nil, ops, bw.crc)); err != nil {
+		return err
+	}
+	return bw.w.Flush()
+}
+
+// crcReader 記錄已讀取的位元組數並計算 CRC32C
+type crcReader struct {
+	r   io.Reader
+	n   int64
+	crc uint32
+}
+
+func (cr *crcReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	cr.crc = crc32.Update(cr.crc, castagnoli, p[:n])
+	return n, err
+}
+
+// Verify 完整讀過 filename，檢查檔頭、中繼資料、操作種類、長度，
+// 以及 version 3 起的 trailer 與 CRC32C。檔案有問題時回傳 *FormatError，
+// 其中記錄第一個問題的位元組偏移與操作索引
+func Verify(filename string) error {
+	br, err := OpenBenchReader(filename)
+	if err != nil {
+		return err
+	}
+	defer br.Close()
+	for br.NextBatch(DefaultBatchSize) != nil {
+	}
+	return br.Err()
+}
@@ -15,6 +15,7 @@ import (
 type ZipfDataGenerator struct {
 	n       int
 	a, b    float64
+	seed    int64
 	Weights []float64
 	cdf     []float64
 	rng     *rand.Rand
@@ -45,6 +46,7 @@ func NewZipfDataGenerator(n int, a, b float64, seed int64) *ZipfDataGenerator {
 		n:       n,
 		a:       a,
 		b:       b,
+		seed:    seed,
 		Weights: weights,
 		cdf:     cdf,
 		rng:     rng,
@@ -1,6 +1,8 @@
 package tlist
 
 import (
+	"cmp"
+
 	"github.com/Hakuto4838/SkipList.git/skiplist"
 )
 
@@ -9,39 +11,50 @@ const (
 	probability = 0.5
 )
 
-type tNode struct {
-	key   skiplist.K
-	value skiplist.V
-	next  []*tNode
+type tNode[K cmp.Ordered, V any] struct {
+	key   K
+	value V
+	next  []*tNode[K, V]
 	del   bool
 }
 
-type TList struct {
-	head  *tNode
+type TList[K cmp.Ordered, V any] struct {
+	head  *tNode[K, V]
 	level int32
 	size  int32
 	span  int32
 }
 
-func newNode(key skiplist.K, value skiplist.V, level int32) *tNode {
-	n := &tNode{
+func newNode[K cmp.Ordered, V any](key K, value V, level int32) *tNode[K, V] {
+	n := &tNode[K, V]{
 		key:   key,
 		value: value,
-		next:  make([]*tNode, level+1),
+		next:  make([]*tNode[K, V], level+1),
 		del:   false,
 	}
 	return n
 }
 
-func NewSkipList(span int32) *TList {
-	return &TList{
-		head:  newNode(-1, 0, maxLevel+1),
+func NewSkipList(span int32) *TList[skiplist.K, skiplist.V] {
+	return newList[skiplist.K, skiplist.V](span, -1)
+}
+
+// NewSkipListOf 建立任意可排序 key 的 TList
+func NewSkipListOf[K cmp.Ordered, V any](span int32) *TList[K, V] {
+	var headKey K
+	return newList[K, V](span, headKey)
+}
+
+func newList[K cmp.Ordered, V any](span int32, headKey K) *TList[K, V] {
+	var zero V
+	return &TList[K, V]{
+		head:  newNode(headKey, zero, maxLevel+1),
 		level: 1, // 初始化為 1 層
 		span:  span,
 	}
 }
 
-func (sl *TList) pureTravel(key skiplist.K) (*tNode, bool) {
+func (sl *TList[K, V]) pureTravel(key K) (*tNode[K, V], bool) {
 	curr := sl.head
 	for level := sl.level - 1; level >= 0; level-- {
 		for curr.next[level] != nil && curr.next[level].key < key {
@@ -54,10 +67,10 @@ func (sl *TList) pureTravel(key skiplist.K) (*tNode, bool) {
 	return curr, false
 }
 
-func (sl *TList) buildTravel(key skiplist.K) (*tNode, bool) {
+func (sl *TList[K, V]) buildTravel(key K) (*tNode[K, V], bool) {
 	curr := sl.head
 	stepCounter := int32(0)
-	var stationPointer *tNode = sl.head
+	var stationPointer *tNode[K, V] = sl.head
 	for level := sl.level - 1; level >= 0; level-- {
 		for curr.next[level] != nil && curr.next[level].key < key {
 			curr = curr.next[level]
@@ -84,7 +97,7 @@ func (sl *TList) buildTravel(key skiplist.K) (*tNode, bool) {
 	return curr, false
 }
 
-func (nd *tNode) upgrade(parent *tNode) {
+func (nd *tNode[K, V]) upgrade(parent *tNode[K, V]) {
 	if nd.GetLevel() >= parent.GetLevel() {
 		return
 	}
@@ -96,77 +109,120 @@ func (nd *tNode) upgrade(parent *tNode) {
 // 實現 SkipList interface 的方法
 
 // Put 插入或更新 key 對應的 value
-func (sl *TList) Put(key skiplist.K, value skiplist.V) {
+func (sl *TList[K, V]) Put(key K, value V) (V, bool) {
+	var zero V
 	node, found := sl.buildTravel(key)
 	if found {
+		old, replaced := node.value, !node.del
+		if node.del {
+			// 已標記刪除的節點視為重新插入
+			old = zero
+			sl.size++
+		}
 		node.value = value
 		node.del = false
-		sl.size++
-		return
+		return old, replaced
 	}
 
 	newNode := newNode(key, value, 0)
 	newNode.next[0] = node.next[0]
 	node.next[0] = newNode
 	sl.size++
+	return zero, false
 }
 
 // Get 取得 key 對應的 value
-func (sl *TList) Get(key skiplist.K) (skiplist.V, bool) {
+func (sl *TList[K, V]) Get(key K) (V, bool) {
+	var zero V
 	node, found := sl.buildTravel(key)
 	if found {
 		if node.del {
-			return 0, false
+			return zero, false
 		}
 		return node.value, true
 	}
-	return 0, false
+	return zero, false
 }
 
 // Contains 判斷 key 是否存在
-func (sl *TList) Contains(key skiplist.K) bool {
+func (sl *TList[K, V]) Contains(key K) bool {
 	node, found := sl.buildTravel(key)
 	return found && !node.del
 }
 
 // Delete 刪除 key
-func (sl *TList) Delete(key skiplist.K) {
+func (sl *TList[K, V]) Delete(key K) (V, bool) {
 	node, found := sl.buildTravel(key)
-	if found {
-		node.del = true
-		sl.size--
+	if !found || node.del {
+		var zero V
+		return zero, false
+	}
+	node.del = true
+	sl.size--
+	return node.value, true
+}
+
+// unlink 將 nd 自所有層實體移除，nd 本身的 next 保持不變，停在 nd 的迭代器仍可繼續前進
+func (sl *TList[K, V]) unlink(nd *tNode[K, V]) {
+	curr := sl.head
+	for level := sl.level - 1; level >= 0; level-- {
+		for curr.next[level] != nil && curr.next[level].key < nd.key {
+			curr = curr.next[level]
+		}
+		if curr.next[level] == nd {
+			curr.next[level] = nd.next[level]
+		}
+	}
+	for sl.level > 1 && sl.head.next[sl.level-1] == nil {
+		sl.level--
 	}
 }
 
+// popped 將已移除的 nd 標記為刪除並回傳其元素
+func (sl *TList[K, V]) popped(nd *tNode[K, V]) (K, V, bool) {
+	nd.del = true
+	sl.size--
+	return nd.key, nd.value, true
+}
+
+// Len 回傳未刪除的元素個數
+func (sl *TList[K, V]) Len() int {
+	return int(sl.size)
+}
+
 // GetHead 實現 SkipList interface
-func (sl *TList) GetHead() skiplist.Nodelike {
+func (sl *TList[K, V]) GetHead() skiplist.NodelikeOf[K, V] {
 	if sl.head == nil {
 		return nil
 	}
 	return sl.head
 }
 
-func (nd *tNode) GetLevel() int32 {
+func (nd *tNode[K, V]) GetLevel() int32 {
 	return int32(len(nd.next) - 1)
 }
 
 // GetKey 實現 Nodelike 介面
-func (nd *tNode) GetKey() skiplist.K {
+func (nd *tNode[K, V]) GetKey() K {
 	return nd.key
 }
 
 // GetValue 實現 Nodelike 介面
-func (nd *tNode) GetValue() skiplist.V {
+func (nd *tNode[K, V]) GetValue() V {
 	return nd.value
 }
 
-func (nd *tNode) GetNextAt(level int32) skiplist.Nodelike {
+func (nd *tNode[K, V]) GetNextAt(level int32) skiplist.NodelikeOf[K, V] {
 	if level < 0 || level >= int32(len(nd.next)) || nd.next[level] == nil {
 		return nil
 	}
 	return nd.next[level]
 }
 
-func (sl *TList) GetMaxStats() (int, int) {
+func (sl *TList[K, V]) GetMaxStats() (int, int) {
 	return int(sl.size), int(sl.level)
 }
+
+func (sl *TList[K, V]) Compare(a, b K) int {
+	return cmp.Compare(a, b)
+}
@@ -1,9 +1,14 @@
 package tlist
 
 import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"sync"
 	"testing"
 
 	"github.com/Hakuto4838/SkipList.git/skiplist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/skiplisttest"
 )
 
 func TestTListBasic(t *testing.T) {
@@ -48,3 +53,354 @@ func TestTListBasic(t *testing.T) {
 	size, level := tl.GetMaxStats()
 	t.Logf("TList 統計: size=%d, level=%d", size, level)
 }
+
+func TestTListGeneric(t *testing.T) {
+	tl := NewSkipListOf[string, int](2)
+	words := []string{"pear", "apple", "fig", "banana", "cherry"}
+	for i, w := range words {
+		tl.Put(w, i)
+	}
+
+	for i, w := range words {
+		if value, found := tl.Get(w); !found || value != i {
+			t.Errorf("Get(%q) = (%d, %v), want (%d, true)", w, value, found, i)
+		}
+	}
+
+	tl.Delete("fig")
+	if tl.Contains("fig") {
+		t.Error("刪除 fig 後，期望不存在，但 Contains 返回 true")
+	}
+}
+
+func TestTListConformance(t *testing.T) {
+	skiplisttest.Run(t, func() skiplist.SkipList { return NewSkipList(2) })
+}
+
+func TestConcurrentTListConformance(t *testing.T) {
+	skiplisttest.Run(t, func() skiplist.SkipList { return NewConcurrentSkipList(2) })
+}
+
+func TestConcurrentTListInterface(t *testing.T) {
+	var _ skiplist.SkipList = (*ConcurrentTList[skiplist.K, skiplist.V])(nil)
+	var _ skiplist.Analyable = (*ConcurrentTList[skiplist.K, skiplist.V])(nil)
+	var _ skiplist.Nodelike = (*ctNode[skiplist.K, skiplist.V])(nil)
+}
+
+// levelKeys 依序收集第 level 層串接的 key
+func levelKeys(head skiplist.Nodelike, level int32) []skiplist.K {
+	var keys []skiplist.K
+	for nd := head.GetNextAt(level); nd != nil; nd = nd.GetNextAt(level) {
+		keys = append(keys, nd.GetKey())
+	}
+	return keys
+}
+
+// 單一 goroutine 時升階規則應與 TList 完全相同
+func TestConcurrentTListMatchesSequential(t *testing.T) {
+	seq := NewSkipList(3)
+	con := NewConcurrentSkipList(3)
+	r := rand.New(rand.NewSource(5))
+	for i := 0; i < 20000; i++ {
+		key := skiplist.K(r.ExpFloat64() * 200)
+		switch r.Intn(8) {
+		case 0:
+			seq.Delete(key)
+			con.Delete(key)
+		case 1, 2:
+			seq.Put(key, skiplist.V(i))
+			con.Put(key, skiplist.V(i))
+		default:
+			seq.Get(key)
+			con.Get(key)
+		}
+	}
+
+	seqSize, seqLevel := seq.GetMaxStats()
+	conSize, conLevel := con.GetMaxStats()
+	if conSize != seqSize || conLevel != seqLevel {
+		t.Fatalf("GetMaxStats() = (%d, %d), want (%d, %d)", conSize, conLevel, seqSize, seqLevel)
+	}
+	for level := int32(0); level < int32(seqLevel); level++ {
+		if got, want := levelKeys(con.GetHead(), level), levelKeys(seq.GetHead(), level); !slices.Equal(got, want) {
+			t.Fatalf("level %d keys = %v, want %v", level, got, want)
+		}
+	}
+}
+
+func TestConcurrentTListConcurrent(t *testing.T) {
+	sl := NewConcurrentSkipList(2)
+	const goroutines = 16
+	const opsPerGoroutine = 5000
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(id)))
+			for i := 0; i < opsPerGoroutine; i++ {
+				key := skiplist.K(r.ExpFloat64() * 300)
+				switch r.Intn(8) {
+				case 0:
+					sl.Delete(key)
+				case 1, 2:
+					sl.Put(key, skiplist.V(key))
+				case 3:
+					sl.Floor(key)
+				default:
+					if v, found := sl.Get(key); found && v != skiplist.V(key) {
+						t.Errorf("Get(%d) = %f, want %d", key, v, key)
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	n := 0
+	prev := skiplist.K(-1)
+	for k := range sl.All() {
+		if k <= prev {
+			t.Fatalf("All() yielded %d after %d", k, prev)
+		}
+		prev = k
+		n++
+	}
+	if n != sl.Len() {
+		t.Errorf("All() yielded %d keys, Len() = %d", n, sl.Len())
+	}
+	// 每一層都必須維持遞增，且上層的節點必定也在下層
+	_, level := sl.GetMaxStats()
+	below := levelKeys(sl.GetHead(), 0)
+	for h := int32(1); h < int32(level); h++ {
+		keys := levelKeys(sl.GetHead(), h)
+		if !slices.IsSorted(keys) {
+			t.Fatalf("level %d keys are not sorted: %v", h, keys)
+		}
+		for _, k := range keys {
+			if _, found := slices.BinarySearch(below, k); !found {
+				t.Fatalf("key %d on level %d is missing from level %d", k, h, h-1)
+			}
+		}
+		below = keys
+	}
+}
+
+// 每個 goroutine 只操作自己的 key，升階與插入交錯時不能漏掉任何節點
+func TestConcurrentTListDisjointKeys(t *testing.T) {
+	sl := NewConcurrentSkipList(2)
+	const goroutines = 8
+	const keysPerGoroutine = 1000
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for i := 0; i < keysPerGoroutine; i++ {
+				key := skiplist.K(i*goroutines + id)
+				sl.Put(key, skiplist.V(key))
+				if i%2 == 1 {
+					sl.Delete(key - goroutines)
+				}
+				if v, found := sl.Get(key); !found || v != skiplist.V(key) {
+					t.Errorf("Get(%d) = (%f, %v) right after Put, want (%d, true)", key, v, found, key)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if want := goroutines * keysPerGoroutine / 2; sl.Len() != want {
+		t.Errorf("Len() = %d, want %d", sl.Len(), want)
+	}
+}
+
+func TestConcurrentTListPopMinUnique(t *testing.T) {
+	sl := NewConcurrentSkipList(2)
+	const n = 4000
+	for i := 0; i < n; i++ {
+		sl.Put(skiplist.K(i), skiplist.V(i))
+	}
+	var mu sync.Mutex
+	seen := make(map[skiplist.K]bool, n)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				k, _, ok := sl.PopMin()
+				if !ok {
+					return
+				}
+				mu.Lock()
+				if seen[k] {
+					t.Errorf("PopMin() returned %d twice", k)
+				}
+				seen[k] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != n {
+		t.Errorf("popped %d distinct keys, want %d", len(seen), n)
+	}
+}
+
+// drainKeys 插入 n 個打亂的 key 並刪除其中三分之一留下墓碑，回傳存活的 key（遞增）
+func drainKeys(sl skiplist.SkipList, n int) []skiplist.K {
+	r := rand.New(rand.NewSource(11))
+	var alive []skiplist.K
+	for _, i := range r.Perm(n) {
+		sl.Put(skiplist.K(i), skiplist.V(i))
+	}
+	for i := 0; i < n; i++ {
+		if i%3 == 1 {
+			sl.Delete(skiplist.K(i))
+		} else {
+			alive = append(alive, skiplist.K(i))
+		}
+	}
+	return alive
+}
+
+// 交替 PopMin/PopMax 清空串列：順序必須正確，且取出的節點與途經的墓碑都已實體移除，
+// 下一次 Pop 不會重新走過，清空的總成本為 O(n log n)
+func TestTListPopDrain(t *testing.T) {
+	sl := NewSkipList(3)
+	alive := drainKeys(sl, 30000)
+	lo, hi := 0, len(alive)-1
+	for i := 0; lo <= hi; i++ {
+		if i%2 == 0 {
+			k, v, ok := sl.PopMin()
+			if !ok || k != alive[lo] || v != skiplist.V(k) {
+				t.Fatalf("PopMin() = (%d, %f, %v), want (%d, %d, true)", k, v, ok, alive[lo], alive[lo])
+			}
+			lo++
+			if first := sl.head.next[0]; first != nil && first.key <= k {
+				t.Fatalf("after PopMin() = %d the first linked node is %d", k, first.key)
+			}
+		} else {
+			k, v, ok := sl.PopMax()
+			if !ok || k != alive[hi] || v != skiplist.V(k) {
+				t.Fatalf("PopMax() = (%d, %f, %v), want (%d, %d, true)", k, v, ok, alive[hi], alive[hi])
+			}
+			hi--
+			if last := sl.findLast(); last != sl.head && last.key >= k {
+				t.Fatalf("after PopMax() = %d the last linked node is %d", k, last.key)
+			}
+		}
+	}
+	if _, _, ok := sl.PopMin(); ok {
+		t.Error("PopMin() on drained list ok = true, want false")
+	}
+	if sl.Len() != 0 || sl.head.next[0] != nil {
+		t.Errorf("drained list has Len() = %d and first node %v, want 0 and nil", sl.Len(), sl.head.next[0])
+	}
+	if sl.level != 1 {
+		t.Errorf("drained list has level %d, want 1", sl.level)
+	}
+}
+
+// ctPhysicalLen 回傳第 0 層實際串接的節點數（含墓碑與標記節點）
+func ctPhysicalLen[K cmp.Ordered, V any](sl *ConcurrentTList[K, V]) int {
+	n := 0
+	for nd := sl.head.next[0].Load(); nd != nil; nd = nd.next[0].Load() {
+		n++
+	}
+	return n
+}
+
+func TestConcurrentTListPopDrain(t *testing.T) {
+	sl := NewConcurrentSkipList(3)
+	alive := drainKeys(sl, 30000)
+	lo, hi := 0, len(alive)-1
+	for i := 0; lo <= hi; i++ {
+		if i%2 == 0 {
+			k, v, ok := sl.PopMin()
+			if !ok || k != alive[lo] || v != skiplist.V(k) {
+				t.Fatalf("PopMin() = (%d, %f, %v), want (%d, %d, true)", k, v, ok, alive[lo], alive[lo])
+			}
+			lo++
+			if first := sl.head.next[0].Load(); first != nil && first.key <= k {
+				t.Fatalf("after PopMin() = %d the first linked node is %d", k, first.key)
+			}
+		} else {
+			k, v, ok := sl.PopMax()
+			if !ok || k != alive[hi] || v != skiplist.V(k) {
+				t.Fatalf("PopMax() = (%d, %f, %v), want (%d, %d, true)", k, v, ok, alive[hi], alive[hi])
+			}
+			hi--
+			if last := sl.findLast(); last != sl.head && last.key >= k {
+				t.Fatalf("after PopMax() = %d the last linked node is %d", k, last.key)
+			}
+		}
+	}
+	if n := ctPhysicalLen(sl); sl.Len() != 0 || n != 0 {
+		t.Errorf("drained list has Len() = %d and %d linked nodes, want 0 and 0", sl.Len(), n)
+	}
+}
+
+// 生產者與 PopMin/PopMax 的消費者同時進行，每個 key 只能被取出一次，
+// 結束後剩下的 key 與取出的 key 合起來恰好是所有插入的 key，且沒有殘留的移除節點
+func TestConcurrentTListPopWhilePut(t *testing.T) {
+	sl := NewConcurrentSkipList(2)
+	const producers, consumers, perProducer = 4, 4, 3000
+	var mu sync.Mutex
+	seen := make(map[skiplist.K]bool, producers*perProducer)
+	var producing, consuming sync.WaitGroup
+	done := make(chan struct{})
+	for g := 0; g < producers; g++ {
+		producing.Add(1)
+		go func(id int) {
+			defer producing.Done()
+			for i := 0; i < perProducer; i++ {
+				sl.Put(skiplist.K(i*producers+id), 1)
+			}
+		}(g)
+	}
+	for g := 0; g < consumers; g++ {
+		consuming.Add(1)
+		go func(id int) {
+			defer consuming.Done()
+			pop := sl.PopMin
+			if id%2 == 1 {
+				pop = sl.PopMax
+			}
+			for {
+				k, _, ok := pop()
+				if !ok {
+					select {
+					case <-done:
+						return
+					default:
+						continue
+					}
+				}
+				mu.Lock()
+				if seen[k] {
+					t.Errorf("key %d popped twice", k)
+				}
+				seen[k] = true
+				mu.Unlock()
+			}
+		}(g)
+	}
+	producing.Wait()
+	close(done)
+	consuming.Wait()
+
+	for k := range sl.All() {
+		if seen[k] {
+			t.Errorf("key %d both popped and still in the list", k)
+		}
+		seen[k] = true
+	}
+	if len(seen) != producers*perProducer {
+		t.Errorf("popped and remaining keys = %d, want %d", len(seen), producers*perProducer)
+	}
+	if n := ctPhysicalLen(sl); n != sl.Len() {
+		t.Errorf("%d linked nodes for Len() = %d, want no leftover removed nodes", n, sl.Len())
+	}
+}
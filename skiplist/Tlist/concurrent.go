@@ -0,0 +1,507 @@
+package tlist
+
+import (
+	"cmp"
+	"iter"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+)
+
+// ctEntry 是不可變的 (value, 刪除標記) 組合，整個替換讓更新值與邏輯刪除不會互相覆蓋
+type ctEntry[V any] struct {
+	value   V
+	del     bool
+	removed bool // 節點已被 PopMin/PopMax 實體移除（或是移除用的標記節點），不可再寫入或當作前驅
+}
+
+type ctNode[K cmp.Ordered, V any] struct {
+	key   K
+	entry atomic.Pointer[ctEntry[V]]
+	mu    sync.Mutex   // 串行化同一節點的升階與移除
+	top   atomic.Int32 // 目前最高層，只增不減，該層連上後才更新
+	next  [maxLevel + 2]atomic.Pointer[ctNode[K, V]]
+}
+
+// ConcurrentTList 可供多個 goroutine 同時使用的 TList。
+// 插入新節點只需在第 0 層 CAS；搜尋途中的升階先取得被升階節點的鎖，再以 CAS 接到上一層，
+// 同一節點不會被重複升階，而同一區間的其他升階或插入會讓 CAS 失敗並重試。
+// Delete 只留下刪除標記；PopMin/PopMax 取出的節點則仿照 Java 的 ConcurrentSkipListMap，
+// 先在節點後各層接上標記節點凍結其 next，再逐層以 CAS 跳過，讀取者停在被移除的節點上仍可前進。
+// 串列的層數只增不減。
+type ConcurrentTList[K cmp.Ordered, V any] struct {
+	head  *ctNode[K, V]
+	level atomic.Int32
+	size  atomic.Int32
+	span  int32
+}
+
+func NewConcurrentSkipList(span int32) *ConcurrentTList[skiplist.K, skiplist.V] {
+	return newConcurrentList[skiplist.K, skiplist.V](span, -1)
+}
+
+// NewConcurrentSkipListOf 建立任意可排序 key 的 ConcurrentTList
+func NewConcurrentSkipListOf[K cmp.Ordered, V any](span int32) *ConcurrentTList[K, V] {
+	var headKey K
+	return newConcurrentList[K, V](span, headKey)
+}
+
+func newConcurrentList[K cmp.Ordered, V any](span int32, headKey K) *ConcurrentTList[K, V] {
+	head := &ctNode[K, V]{key: headKey}
+	head.top.Store(maxLevel + 1)
+	sl := &ConcurrentTList[K, V]{head: head, span: span}
+	sl.level.Store(1) // 初始化為 1 層
+	return sl
+}
+
+// removed 判斷節點是否已被實體移除或是標記節點，head 的 entry 為 nil
+func (nd *ctNode[K, V]) removed() bool {
+	e := nd.entry.Load()
+	return e != nil && e.removed
+}
+
+// before 判斷 nd 是否排在 key 的節點之前；同 key 的已移除節點與標記節點排在存活節點之前
+func (nd *ctNode[K, V]) before(key K) bool {
+	return nd.key < key || nd.key == key && nd.removed()
+}
+
+// buildTravel 與 TList.buildTravel 相同的升階規則，回傳找到的節點或第 0 層最後一個排在 key 之前的節點
+func (sl *ConcurrentTList[K, V]) buildTravel(key K) (*ctNode[K, V], bool) {
+	curr := sl.head
+	stepCounter := int32(0)
+	stationPointer := sl.head
+	for level := sl.level.Load() - 1; level >= 0; level-- {
+		for next := curr.next[level].Load(); next != nil && next.before(key); next = curr.next[level].Load() {
+			curr = next
+
+			stepCounter++
+			if stepCounter >= sl.span && level < maxLevel {
+				// 升階判定
+				if after := curr.next[level].Load(); after == nil || after.top.Load() <= level {
+					curr.upgrade(stationPointer, level)
+					stationPointer = curr
+					sl.level.CompareAndSwap(level+1, level+2)
+				}
+				stepCounter = 0
+			}
+		}
+		if next := curr.next[level].Load(); next != nil && next.key == key {
+			return next, true
+		}
+		stationPointer = curr
+		stepCounter = 0
+	}
+	return curr, false
+}
+
+// upgrade 將位於第 level 層的 nd 接到第 level+1 層。
+// parent 是搜尋路徑上高度至少 level+1 且 key 小於 nd 的節點；
+// 其他 goroutine 可能已在兩者之間接上節點，因此先沿著該層往後找到 nd 的前驅。
+// nd 或前驅已被移除時放棄升階。
+func (nd *ctNode[K, V]) upgrade(parent *ctNode[K, V], level int32) {
+	lvl := level + 1
+	if parent.top.Load() < lvl {
+		return
+	}
+	nd.mu.Lock()
+	defer nd.mu.Unlock()
+	if nd.top.Load() != level || nd.removed() {
+		// 已被其他 goroutine 升階或移除
+		return
+	}
+	for {
+		succ := parent.next[lvl].Load()
+		if succ != nil && succ.before(nd.key) {
+			parent = succ
+			continue
+		}
+		if parent.removed() {
+			return
+		}
+		// 先設定 next 再公布，經由上一層走到 nd 的讀取者不會看到未完成的串接
+		nd.next[lvl].Store(succ)
+		if parent.next[lvl].CompareAndSwap(succ, nd) {
+			nd.top.Store(lvl)
+			return
+		}
+	}
+}
+
+// store 更新既有節點的值，節點已被標記刪除時視為重新插入；
+// 節點已被實體移除時 ok 為 false，需重新搜尋
+func (sl *ConcurrentTList[K, V]) store(node *ctNode[K, V], value V) (old V, replaced, ok bool) {
+	var zero V
+	next := &ctEntry[V]{value: value}
+	for {
+		e := node.entry.Load()
+		if e.removed {
+			return zero, false, false
+		}
+		if node.entry.CompareAndSwap(e, next) {
+			if e.del {
+				sl.size.Add(1)
+				return zero, false, true
+			}
+			return e.value, true, true
+		}
+	}
+}
+
+// Put 插入或更新 key 對應的 value
+func (sl *ConcurrentTList[K, V]) Put(key K, value V) (V, bool) {
+	for {
+		pred, found := sl.buildTravel(key)
+		if found {
+			if old, replaced, ok := sl.store(pred, value); ok {
+				return old, replaced
+			}
+			continue
+		}
+		if old, replaced, ok := sl.insert(pred, key, value); ok {
+			return old, replaced
+		}
+	}
+}
+
+// insert 由 pred 往後在第 0 層插入新節點；pred 已被移除時 ok 為 false，需重新搜尋
+func (sl *ConcurrentTList[K, V]) insert(pred *ctNode[K, V], key K, value V) (old V, replaced, ok bool) {
+	var zero V
+	newNode := &ctNode[K, V]{key: key}
+	newNode.entry.Store(&ctEntry[V]{value: value})
+	for {
+		if pred.removed() {
+			// 標記節點的 next 不可修改，被移除節點的 next 也即將凍結
+			return zero, false, false
+		}
+		succ := pred.next[0].Load()
+		if succ != nil && succ.before(key) {
+			pred = succ
+			continue
+		}
+		if succ != nil && succ.key == key {
+			// 搜尋後被其他 goroutine 插入
+			return sl.store(succ, value)
+		}
+		newNode.next[0].Store(succ)
+		if pred.next[0].CompareAndSwap(succ, newNode) {
+			sl.size.Add(1)
+			return zero, false, true
+		}
+	}
+}
+
+// Get 取得 key 對應的 value
+func (sl *ConcurrentTList[K, V]) Get(key K) (V, bool) {
+	var zero V
+	node, found := sl.buildTravel(key)
+	if !found {
+		return zero, false
+	}
+	e := node.entry.Load()
+	if e.del {
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Contains 判斷 key 是否存在
+func (sl *ConcurrentTList[K, V]) Contains(key K) bool {
+	node, found := sl.buildTravel(key)
+	return found && !node.entry.Load().del
+}
+
+// Delete 刪除 key
+func (sl *ConcurrentTList[K, V]) Delete(key K) (V, bool) {
+	var zero V
+	node, found := sl.buildTravel(key)
+	if !found {
+		return zero, false
+	}
+	for {
+		e := node.entry.Load()
+		if e.del {
+			return zero, false
+		}
+		if node.entry.CompareAndSwap(e, &ctEntry[V]{value: e.value, del: true}) {
+			sl.size.Add(-1)
+			return e.value, true
+		}
+	}
+}
+
+// Len 回傳未刪除的元素個數
+func (sl *ConcurrentTList[K, V]) Len() int {
+	return int(sl.size.Load())
+}
+
+func (sl *ConcurrentTList[K, V]) GetHead() skiplist.NodelikeOf[K, V] {
+	return sl.head
+}
+
+func (sl *ConcurrentTList[K, V]) GetMaxStats() (int, int) {
+	return sl.Len(), int(sl.level.Load())
+}
+
+func (sl *ConcurrentTList[K, V]) Compare(a, b K) int {
+	return cmp.Compare(a, b)
+}
+
+func (nd *ctNode[K, V]) GetLevel() int32 {
+	return nd.top.Load()
+}
+
+func (nd *ctNode[K, V]) GetKey() K {
+	return nd.key
+}
+
+func (nd *ctNode[K, V]) GetValue() V {
+	var zero V
+	if e := nd.entry.Load(); e != nil {
+		return e.value
+	}
+	return zero
+}
+
+func (nd *ctNode[K, V]) GetNextAt(level int32) skiplist.NodelikeOf[K, V] {
+	if level < 0 || level > nd.top.Load() {
+		return nil
+	}
+	next := nd.next[level].Load()
+	if next == nil {
+		return nil
+	}
+	return next
+}
+
+// ctIterator 是弱一致的迭代器，只使用不升階的搜尋
+type ctIterator[K cmp.Ordered, V any] struct {
+	sl    *ConcurrentTList[K, V]
+	cur   *ctNode[K, V]
+	entry *ctEntry[V] // 移到 cur 時讀到的值
+}
+
+// findLess 回傳最後一個 key 小於指定 key 的節點（含已刪除），沒有時回傳 head
+func (sl *ConcurrentTList[K, V]) findLess(key K) *ctNode[K, V] {
+	curr := sl.head
+	for level := sl.level.Load() - 1; level >= 0; level-- {
+		for next := curr.next[level].Load(); next != nil && next.key < key; next = curr.next[level].Load() {
+			curr = next
+		}
+	}
+	return curr
+}
+
+// findLast 回傳最後一個節點（含已刪除），串列為空時回傳 head
+func (sl *ConcurrentTList[K, V]) findLast() *ctNode[K, V] {
+	curr := sl.head
+	for level := sl.level.Load() - 1; level >= 0; level-- {
+		for next := curr.next[level].Load(); next != nil; next = curr.next[level].Load() {
+			curr = next
+		}
+	}
+	return curr
+}
+
+// seekAlive 由 nd 往後找第一個未刪除的節點，nd 本身也列入考慮
+func (it *ctIterator[K, V]) seekAlive(nd *ctNode[K, V]) {
+	for ; nd != nil; nd = nd.next[0].Load() {
+		if e := nd.entry.Load(); !e.del {
+			it.cur, it.entry = nd, e
+			return
+		}
+	}
+	it.cur, it.entry = nil, nil
+}
+
+// seekAliveBefore 由 nd 往前找第一個未刪除的節點，nd 本身也列入考慮
+func (it *ctIterator[K, V]) seekAliveBefore(nd *ctNode[K, V]) {
+	for nd != it.sl.head {
+		if e := nd.entry.Load(); !e.del {
+			it.cur, it.entry = nd, e
+			return
+		}
+		nd = it.sl.findLess(nd.key)
+	}
+	it.cur, it.entry = nil, nil
+}
+
+func (sl *ConcurrentTList[K, V]) Iterator() skiplist.IteratorOf[K, V] {
+	return &ctIterator[K, V]{sl: sl}
+}
+
+func (sl *ConcurrentTList[K, V]) Range(lo, hi K, fn func(key K, value V) bool) {
+	skiplist.RangeOf(sl.Iterator(), cmp.Compare[K], lo, hi, fn)
+}
+
+func (sl *ConcurrentTList[K, V]) All() iter.Seq2[K, V] {
+	return skiplist.AllOf(sl.Iterator)
+}
+
+func (sl *ConcurrentTList[K, V]) Backward() iter.Seq2[K, V] {
+	return skiplist.BackwardOf(sl.Iterator)
+}
+
+// recordAccess 將查詢回傳的元素視為一次存取，以 buildTravel 走一次升階路徑
+func (sl *ConcurrentTList[K, V]) recordAccess(k K, v V, ok bool) (K, V, bool) {
+	if ok {
+		sl.buildTravel(k)
+	}
+	return k, v, ok
+}
+
+func (sl *ConcurrentTList[K, V]) Floor(key K) (K, V, bool) {
+	return sl.recordAccess(skiplist.FloorOf(sl.Iterator(), cmp.Compare[K], key))
+}
+
+func (sl *ConcurrentTList[K, V]) Ceiling(key K) (K, V, bool) {
+	return sl.recordAccess(skiplist.CeilingOf(sl.Iterator(), key))
+}
+
+func (sl *ConcurrentTList[K, V]) Predecessor(key K) (K, V, bool) {
+	return sl.recordAccess(skiplist.PredecessorOf(sl.Iterator(), key))
+}
+
+func (sl *ConcurrentTList[K, V]) Successor(key K) (K, V, bool) {
+	return sl.recordAccess(skiplist.SuccessorOf(sl.Iterator(), cmp.Compare[K], key))
+}
+
+func (sl *ConcurrentTList[K, V]) Min() (K, V, bool) {
+	return skiplist.MinOf(sl.Iterator())
+}
+
+func (sl *ConcurrentTList[K, V]) Max() (K, V, bool) {
+	return skiplist.MaxOf(sl.Iterator())
+}
+
+// PopMin 取出最小元素並實體移除，途經的已刪除節點一併移除；
+// 只回傳自己成功移除的元素，多個消費者不會取得同一個元素
+func (sl *ConcurrentTList[K, V]) PopMin() (K, V, bool) {
+	for nd := sl.head.next[0].Load(); nd != nil; nd = nd.next[0].Load() {
+		if e, ok := sl.remove(nd); ok && !e.del {
+			return nd.key, e.value, true
+		}
+	}
+	var k K
+	var v V
+	return k, v, false
+}
+
+// PopMax 取出最大元素並實體移除，途經的已刪除節點一併移除
+func (sl *ConcurrentTList[K, V]) PopMax() (K, V, bool) {
+	for nd := sl.findLast(); nd != sl.head; {
+		e, ok := sl.remove(nd)
+		if !ok {
+			// 已被其他 goroutine 移除或是標記節點
+			nd = sl.findLess(nd.key)
+			continue
+		}
+		if !e.del {
+			return nd.key, e.value, true
+		}
+		nd = sl.findLast()
+	}
+	var k K
+	var v V
+	return k, v, false
+}
+
+// remove 將 nd 標記為已移除並自各層實體移除，回傳標記前的 entry；
+// nd 已被其他 goroutine 移除時 ok 為 false
+func (sl *ConcurrentTList[K, V]) remove(nd *ctNode[K, V]) (*ctEntry[V], bool) {
+	nd.mu.Lock()
+	var e *ctEntry[V]
+	for {
+		e = nd.entry.Load()
+		if e.removed {
+			nd.mu.Unlock()
+			return nil, false
+		}
+		if nd.entry.CompareAndSwap(e, &ctEntry[V]{value: e.value, del: true, removed: true}) {
+			break
+		}
+	}
+	// 持有 nd.mu 時標記，之後不會再升階，top 不再改變
+	top := nd.top.Load()
+	nd.mu.Unlock()
+	if !e.del {
+		sl.size.Add(-1)
+	}
+
+	// 由下往上接上標記節點，經由較高層走到標記節點的讀取者往下時 next 都已設定
+	marker := &ctNode[K, V]{key: nd.key}
+	marker.entry.Store(&ctEntry[V]{del: true, removed: true})
+	marker.top.Store(top)
+	for h := int32(0); h <= top; h++ {
+		for {
+			succ := nd.next[h].Load()
+			marker.next[h].Store(succ)
+			if nd.next[h].CompareAndSwap(succ, marker) {
+				break
+			}
+		}
+	}
+	for h := top; h >= 0; h-- {
+		sl.unlinkAt(nd, marker, h)
+	}
+	return e, true
+}
+
+// unlinkAt 在第 h 層以 CAS 同時跳過 nd 與其標記節點
+func (sl *ConcurrentTList[K, V]) unlinkAt(nd, marker *ctNode[K, V], h int32) {
+	for {
+		pred := sl.head
+		for level := sl.level.Load() - 1; level >= h; level-- {
+			for next := pred.next[level].Load(); next != nil && next.key < nd.key; next = pred.next[level].Load() {
+				pred = next
+			}
+		}
+		// 同 key 的已移除節點排在 nd 之前
+		next := pred.next[h].Load()
+		for next != nil && next != nd && next.key == nd.key {
+			pred = next
+			next = pred.next[h].Load()
+		}
+		if pred.removed() {
+			// 可能經由已跳過的節點走到過時的位置，或前驅正在被移除；讓出執行權後重新搜尋
+			runtime.Gosched()
+			continue
+		}
+		// pred 未被移除，必定仍串接在第 h 層，它的 next 可信
+		if next != nd {
+			return
+		}
+		if pred.next[h].CompareAndSwap(nd, marker.next[h].Load()) {
+			return
+		}
+	}
+}
+
+func (it *ctIterator[K, V]) Valid() bool { return it.cur != nil }
+func (it *ctIterator[K, V]) Key() K      { return it.cur.key }
+func (it *ctIterator[K, V]) Value() V    { return it.entry.value }
+
+func (it *ctIterator[K, V]) Next() {
+	it.seekAlive(it.cur.next[0].Load())
+}
+
+func (it *ctIterator[K, V]) Prev() {
+	it.SeekBefore(it.cur.key)
+}
+
+func (it *ctIterator[K, V]) SeekBefore(key K) {
+	it.seekAliveBefore(it.sl.findLess(key))
+}
+
+func (it *ctIterator[K, V]) Seek(key K) {
+	it.seekAlive(it.sl.findLess(key).next[0].Load())
+}
+
+func (it *ctIterator[K, V]) SeekToFirst() {
+	it.seekAlive(it.sl.head.next[0].Load())
+}
+
+func (it *ctIterator[K, V]) SeekToLast() {
+	it.seekAliveBefore(it.sl.findLast())
+}
@@ -0,0 +1,159 @@
+package tlist
+
+import (
+	"cmp"
+	"iter"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+)
+
+// tIterator 走訪時只使用不升階的搜尋，不會改變結構
+type tIterator[K cmp.Ordered, V any] struct {
+	sl  *TList[K, V]
+	cur *tNode[K, V]
+}
+
+// findLess 回傳最後一個 key 小於指定 key 的節點（含已刪除），沒有時回傳 head
+func (sl *TList[K, V]) findLess(key K) *tNode[K, V] {
+	curr := sl.head
+	for level := sl.level - 1; level >= 0; level-- {
+		for curr.next[level] != nil && curr.next[level].key < key {
+			curr = curr.next[level]
+		}
+	}
+	return curr
+}
+
+// findLast 回傳最後一個節點（含已刪除），串列為空時回傳 head
+func (sl *TList[K, V]) findLast() *tNode[K, V] {
+	curr := sl.head
+	for level := sl.level - 1; level >= 0; level-- {
+		for curr.next[level] != nil {
+			curr = curr.next[level]
+		}
+	}
+	return curr
+}
+
+// nextAlive 回傳 nd 之後第一個未刪除的節點
+func (sl *TList[K, V]) nextAlive(nd *tNode[K, V]) *tNode[K, V] {
+	nd = nd.next[0]
+	for nd != nil && nd.del {
+		nd = nd.next[0]
+	}
+	return nd
+}
+
+// prevAlive 由 nd 往前找第一個未刪除的節點，nd 本身也列入考慮
+func (sl *TList[K, V]) prevAlive(nd *tNode[K, V]) *tNode[K, V] {
+	for nd != sl.head && nd.del {
+		nd = sl.findLess(nd.key)
+	}
+	if nd == sl.head {
+		return nil
+	}
+	return nd
+}
+
+func (sl *TList[K, V]) Iterator() skiplist.IteratorOf[K, V] {
+	return &tIterator[K, V]{sl: sl}
+}
+
+func (sl *TList[K, V]) Range(lo, hi K, fn func(key K, value V) bool) {
+	skiplist.RangeOf(sl.Iterator(), cmp.Compare[K], lo, hi, fn)
+}
+
+func (sl *TList[K, V]) All() iter.Seq2[K, V] {
+	return skiplist.AllOf(sl.Iterator)
+}
+
+func (sl *TList[K, V]) Backward() iter.Seq2[K, V] {
+	return skiplist.BackwardOf(sl.Iterator)
+}
+
+// recordAccess 將查詢回傳的元素視為一次存取，以 buildTravel 走一次升階路徑
+func (sl *TList[K, V]) recordAccess(k K, v V, ok bool) (K, V, bool) {
+	if ok {
+		sl.buildTravel(k)
+	}
+	return k, v, ok
+}
+
+func (sl *TList[K, V]) Floor(key K) (K, V, bool) {
+	return sl.recordAccess(skiplist.FloorOf(sl.Iterator(), cmp.Compare[K], key))
+}
+
+func (sl *TList[K, V]) Ceiling(key K) (K, V, bool) {
+	return sl.recordAccess(skiplist.CeilingOf(sl.Iterator(), key))
+}
+
+func (sl *TList[K, V]) Predecessor(key K) (K, V, bool) {
+	return sl.recordAccess(skiplist.PredecessorOf(sl.Iterator(), key))
+}
+
+func (sl *TList[K, V]) Successor(key K) (K, V, bool) {
+	return sl.recordAccess(skiplist.SuccessorOf(sl.Iterator(), cmp.Compare[K], key))
+}
+
+func (sl *TList[K, V]) Min() (K, V, bool) {
+	return skiplist.MinOf(sl.Iterator())
+}
+
+func (sl *TList[K, V]) Max() (K, V, bool) {
+	return skiplist.MaxOf(sl.Iterator())
+}
+
+// PopMin 取出最小元素並將其實體移除，途經的已刪除節點一併移除，
+// 連續取出時不會反覆走過墓碑
+func (sl *TList[K, V]) PopMin() (K, V, bool) {
+	for nd := sl.head.next[0]; nd != nil; nd = sl.head.next[0] {
+		sl.unlink(nd)
+		if !nd.del {
+			return sl.popped(nd)
+		}
+	}
+	var k K
+	var v V
+	return k, v, false
+}
+
+// PopMax 取出最大元素並將其實體移除，途經的已刪除節點一併移除
+func (sl *TList[K, V]) PopMax() (K, V, bool) {
+	for nd := sl.findLast(); nd != sl.head; nd = sl.findLast() {
+		sl.unlink(nd)
+		if !nd.del {
+			return sl.popped(nd)
+		}
+	}
+	var k K
+	var v V
+	return k, v, false
+}
+
+func (it *tIterator[K, V]) Valid() bool { return it.cur != nil }
+func (it *tIterator[K, V]) Key() K      { return it.cur.key }
+func (it *tIterator[K, V]) Value() V    { return it.cur.value }
+
+func (it *tIterator[K, V]) Next() {
+	it.cur = it.sl.nextAlive(it.cur)
+}
+
+func (it *tIterator[K, V]) Prev() {
+	it.SeekBefore(it.cur.key)
+}
+
+func (it *tIterator[K, V]) SeekBefore(key K) {
+	it.cur = it.sl.prevAlive(it.sl.findLess(key))
+}
+
+func (it *tIterator[K, V]) Seek(key K) {
+	it.cur = it.sl.nextAlive(it.sl.findLess(key))
+}
+
+func (it *tIterator[K, V]) SeekToFirst() {
+	it.cur = it.sl.nextAlive(it.sl.head)
+}
+
+func (it *tIterator[K, V]) SeekToLast() {
+	it.cur = it.sl.prevAlive(it.sl.findLast())
+}
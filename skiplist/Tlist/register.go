@@ -0,0 +1,44 @@
+package tlist
+
+import (
+	"fmt"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/registry"
+)
+
+func init() {
+	registry.Register(registry.Impl{
+		Name:   "tlist",
+		Usage:  "搜尋時依路徑長度升階的 T-list",
+		Params: []registry.Param{{Name: "span", Kind: registry.Int, Default: "2", Usage: "搜尋時同層連續經過幾個節點就升階"}},
+		New: func(p registry.Params, env registry.Env) (skiplist.SkipList, error) {
+			span, err := spanParam(p)
+			if err != nil {
+				return nil, err
+			}
+			return NewSkipList(span), nil
+		},
+	})
+	registry.Register(registry.Impl{
+		Name:       "tlist-concurrent",
+		Usage:      "以 CAS 插入、逐節點加鎖升層的 tlist",
+		ThreadSafe: true,
+		Params:     []registry.Param{{Name: "span", Kind: registry.Int, Default: "2", Usage: "搜尋時同層連續經過幾個節點就升階"}},
+		New: func(p registry.Params, env registry.Env) (skiplist.SkipList, error) {
+			span, err := spanParam(p)
+			if err != nil {
+				return nil, err
+			}
+			return NewConcurrentSkipList(span), nil
+		},
+	})
+}
+
+func spanParam(p registry.Params) (int32, error) {
+	span := p.Int("span")
+	if span < 1 || span > 1<<20 {
+		return 0, fmt.Errorf("span must be in [1, %d], got %d", 1<<20, span)
+	}
+	return int32(span), nil
+}
@@ -12,6 +12,11 @@ type StepMap map[skiplist.K]int
 
 // FindStep 計算找到指定 key 的總步數和各層步數
 func FindStep(sl skiplist.Analyable, key skiplist.K) (step int, level []int) {
+	return FindStepOf(sl, key)
+}
+
+// FindStepOf 為泛型版本的 FindStep，以 sl.Compare 比較 key
+func FindStepOf[K, V any](sl skiplist.AnalyableOf[K, V], key K) (step int, level []int) {
 	cur := sl.GetHead()
 	if cur == nil {
 		return 0, []int{}
@@ -30,7 +35,7 @@ func FindStep(sl skiplist.Analyable, key skiplist.K) (step int, level []int) {
 		// 在當前層級水平移動
 		for cur != nil {
 			nextNode := cur.GetNextAt(int32(h))
-			if nextNode == nil || nextNode.GetKey() >= key {
+			if nextNode == nil || sl.Compare(nextNode.GetKey(), key) >= 0 {
 				break
 			}
 			cur = nextNode
@@ -40,7 +45,7 @@ func FindStep(sl skiplist.Analyable, key skiplist.K) (step int, level []int) {
 		// 如果找到目標 key，記錄步數並返回
 		if cur != nil {
 			nextNode := cur.GetNextAt(int32(h))
-			if nextNode != nil && nextNode.GetKey() == key {
+			if nextNode != nil && sl.Compare(nextNode.GetKey(), key) == 0 {
 				levelSteps++ // 加上最後一步
 				stepsPerLevel[h] = levelSteps
 				totalSteps += levelSteps
@@ -59,18 +64,24 @@ func FindStep(sl skiplist.Analyable, key skiplist.K) (step int, level []int) {
 
 // AnalyzeStep 根據 map 提供的 key 出現機率計算平均搜尋步數
 func AnalyzeStep(sl skiplist.Analyable, keys map[skiplist.K]float64) (float64, StepMap) {
+	score, step := AnalyzeStepOf(sl, keys)
+	return score, StepMap(step)
+}
+
+// AnalyzeStepOf 為泛型版本的 AnalyzeStep，key 需可作為 map 的 key
+func AnalyzeStepOf[K comparable, V any](sl skiplist.AnalyableOf[K, V], keys map[K]float64) (float64, map[K]int) {
 	if len(keys) == 0 {
 		return 0.0, nil
 	}
 
-	step := StepMap{}
+	step := map[K]int{}
 
 	var totalExpectedSteps float64
 	var totalProbability float64
 
 	// 遞迴搜尋所有node，若存在key則計算期望步數
-	var dfs func(node skiplist.Nodelike, level int, steps int)
-	dfs = func(node skiplist.Nodelike, level int, steps int) {
+	var dfs func(node skiplist.NodelikeOf[K, V], level int, steps int)
+	dfs = func(node skiplist.NodelikeOf[K, V], level int, steps int) {
 		if node == nil {
 			return
 		}
@@ -83,7 +94,7 @@ func AnalyzeStep(sl skiplist.Analyable, keys map[skiplist.K]float64) (float64, S
 				// fmt.Printf("totalExpectedSteps: %f, totalProbability: %f\n", totalExpectedSteps, totalProbability)
 				step[node.GetKey()] = steps
 			} else {
-				fmt.Printf("warning: key not found in keys map: %d\n", node.GetKey())
+				fmt.Printf("warning: key not found in keys map: %v\n", node.GetKey())
 			}
 		}
 		if level > 0 { // 下降也算一步
@@ -179,7 +190,7 @@ func PrintSkipListToCSV(sl skiplist.Analyable, maxLevel, maxNodes int, writer *c
 				}
 				node = node.GetNextAt(0)
 				currentKeyIndex++
-			} else if node.GetKey() < allKeys[currentKeyIndex] {
+			} else if sl.Compare(node.GetKey(), allKeys[currentKeyIndex]) < 0 {
 				node = node.GetNextAt(0)
 			} else {
 				// This case should ideally not happen if allKeys are from the skiplist
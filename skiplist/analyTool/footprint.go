@@ -0,0 +1,77 @@
+package analyTool
+
+import (
+	"reflect"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+)
+
+// Footprint 為節點結構佔用空間的估計
+type Footprint struct {
+	Nodes    int   // 走訪到的節點數，含 head 與邏輯刪除但仍在串列中的節點
+	Bytes    int64 // 節點本身加上其 slice 欄位底層陣列的大小，不含指標指向的其他物件
+	Pointers int64 // 節點中指標欄位的個數，含固定大小陣列中未使用的欄位
+}
+
+// PerKey 回傳平均每個 key 的位元組數與指標數，keys 為 0 時回傳 0
+func (f Footprint) PerKey(keys int) (bytes, pointers float64) {
+	if keys == 0 {
+		return 0, 0
+	}
+	return float64(f.Bytes) / float64(keys), float64(f.Pointers) / float64(keys)
+}
+
+// MeasureFootprint 沿第 0 層走訪 sl 的所有節點，以 reflect 估計節點佔用的空間
+func MeasureFootprint(sl skiplist.SkipList) Footprint {
+	return MeasureFootprintOf(sl)
+}
+
+// MeasureFootprintOf 為泛型版本的 MeasureFootprint，GetHead 回傳 nil 時結果為零值
+func MeasureFootprintOf[K, V any](sl skiplist.SkipListOf[K, V]) Footprint {
+	var f Footprint
+	for node := sl.GetHead(); node != nil; node = node.GetNextAt(0) {
+		v := reflect.ValueOf(node)
+		if v.Kind() == reflect.Pointer {
+			v = v.Elem()
+		}
+		bytes, pointers := valueSize(v)
+		f.Nodes++
+		f.Bytes += int64(v.Type().Size()) + bytes
+		f.Pointers += pointerSlots(v.Type()) + pointers
+	}
+	return f
+}
+
+// valueSize 回傳 v 內 slice 欄位底層陣列的大小與其中的指標數
+func valueSize(v reflect.Value) (bytes, pointers int64) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			b, p := valueSize(v.Field(i))
+			bytes += b
+			pointers += p
+		}
+	case reflect.Slice:
+		elem := v.Type().Elem()
+		bytes = int64(v.Cap()) * int64(elem.Size())
+		pointers = int64(v.Cap()) * pointerSlots(elem)
+	}
+	return bytes, pointers
+}
+
+// pointerSlots 回傳型別 t 本身（不含 slice 底層陣列）的指標欄位數
+func pointerSlots(t reflect.Type) int64 {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.UnsafePointer:
+		return 1
+	case reflect.Array:
+		return int64(t.Len()) * pointerSlots(t.Elem())
+	case reflect.Struct:
+		n := int64(0)
+		for i := 0; i < t.NumField(); i++ {
+			n += pointerSlots(t.Field(i).Type)
+		}
+		return n
+	}
+	return 0
+}
@@ -1,6 +1,7 @@
 package basic
 
 import (
+	"cmp"
 	"math/rand"
 
 	"github.com/Hakuto4838/SkipList.git/skiplist"
@@ -11,50 +12,76 @@ const (
 	probability = 0.5
 )
 
-type basicNode struct {
-	key   skiplist.K
-	value skiplist.V
-	next  []*basicNode
+type basicNode[K, V any] struct {
+	key   K
+	value V
+	next  []*basicNode[K, V]
+	span  []int32 // span[h]：沿第 h 層走到 next[h] 會跨過幾個節點，next[h] 為 nil 時算到尾端
 }
 
-type BasicSkipList struct {
-	head  *basicNode
-	level int32
-	rand  *rand.Rand
-	size  int32
+type BasicSkipList[K, V any] struct {
+	head    *basicNode[K, V]
+	level   int32
+	rand    *rand.Rand
+	size    int32
+	compare skiplist.Comparator[K]
 }
 
-func NewBasicSkipList(seed int64) *BasicSkipList {
-	return &BasicSkipList{
-		head:  newNode(-1, 0, maxLevel),
-		level: 1,
-		rand:  rand.New(rand.NewSource(seed)),
-		size:  0,
+func NewBasicSkipList(seed int64) *BasicSkipList[skiplist.K, skiplist.V] {
+	return newList[skiplist.K, skiplist.V](seed, -1, cmp.Compare[skiplist.K])
+}
+
+// NewBasicSkipListOf 建立任意可排序 key 的 BasicSkipList
+func NewBasicSkipListOf[K cmp.Ordered, V any](seed int64) *BasicSkipList[K, V] {
+	var headKey K
+	return newList[K, V](seed, headKey, cmp.Compare[K])
+}
+
+// NewBasicSkipListFunc 建立以 compare 決定 key 順序的 BasicSkipList，
+// 可用於 []byte 或複合 key 等無法直接以 < 比較的型別
+func NewBasicSkipListFunc[K, V any](seed int64, compare func(a, b K) int) *BasicSkipList[K, V] {
+	var headKey K
+	return newList[K, V](seed, headKey, compare)
+}
+
+func newList[K, V any](seed int64, headKey K, compare skiplist.Comparator[K]) *BasicSkipList[K, V] {
+	var zero V
+	head := newNode(headKey, zero, maxLevel)
+	for h := range head.span {
+		head.span[h] = 1
+	}
+	return &BasicSkipList[K, V]{
+		head:    head,
+		level:   1,
+		rand:    rand.New(rand.NewSource(seed)),
+		size:    0,
+		compare: compare,
 	}
 }
 
-func (sl *BasicSkipList) find(key skiplist.K) *basicNode {
+func (sl *BasicSkipList[K, V]) find(key K) *basicNode[K, V] {
 	cur := sl.head
 	for h := sl.level; h >= 0; h-- {
-		for cur.next[h] != nil && cur.next[h].key < key {
+		for cur.next[h] != nil && sl.compare(cur.next[h].key, key) < 0 {
 			cur = cur.next[h]
 		}
-		if cur.next[h] != nil && cur.next[h].key == key {
+		if cur.next[h] != nil && sl.compare(cur.next[h].key, key) == 0 {
 			return cur.next[h]
 		}
 	}
 	return nil
 }
 
-func newNode(key skiplist.K, value skiplist.V, level int32) *basicNode {
-	return &basicNode{
+func newNode[K, V any](key K, value V, level int32) *basicNode[K, V] {
+	return &basicNode[K, V]{
 		key:   key,
 		value: value,
-		next:  make([]*basicNode, level+1),
+		next:  make([]*basicNode[K, V], level+1),
+		span:  make([]int32, level+1),
 	}
 }
 
-func (sl *BasicSkipList) randomLevel() int32 {
+func (sl *BasicSkipList[K, V]) randomLevel() int32 {
 	lvl := 0
 	for sl.rand.Float64() < probability && lvl < maxLevel {
 		lvl++
@@ -62,74 +89,117 @@ func (sl *BasicSkipList) randomLevel() int32 {
 	return int32(lvl)
 }
 
-func (sl *BasicSkipList) Put(key skiplist.K, value skiplist.V) {
+func (sl *BasicSkipList[K, V]) Put(key K, value V) (V, bool) {
 	cur := sl.find(key)
 	if cur != nil {
+		old := cur.value
 		cur.value = value
-		return
+		return old, true
 	}
 	lvl := sl.randomLevel()
 	cur = newNode(key, value, lvl)
+	for h := sl.level + 1; h <= lvl; h++ {
+		sl.head.span[h] = sl.size + 1
+	}
 	sl.level = max(sl.level, lvl)
+
+	var preds [maxLevel + 1]*basicNode[K, V]
+	var rank [maxLevel + 1]int32 // rank[h]：第 h 層前驅節點的排名，head 為 0
 	curr := sl.head
 	for h := sl.level; h >= 0; h-- {
-		for curr.next[h] != nil && curr.next[h].key < key {
+		if h < sl.level {
+			rank[h] = rank[h+1]
+		}
+		for curr.next[h] != nil && sl.compare(curr.next[h].key, key) < 0 {
+			rank[h] += curr.span[h]
 			curr = curr.next[h]
 		}
+		preds[h] = curr
+	}
+	for h := int32(0); h <= sl.level; h++ {
+		pred := preds[h]
 		if h <= lvl {
-			cur.next[h] = curr.next[h]
-			curr.next[h] = cur
+			cur.next[h] = pred.next[h]
+			pred.next[h] = cur
+			cur.span[h] = pred.span[h] - (rank[0] - rank[h])
+			pred.span[h] = rank[0] - rank[h] + 1
+		} else {
+			pred.span[h]++
 		}
-		sl.size++
 	}
+	sl.size++
+	var zero V
+	return zero, false
 }
 
-func (sl *BasicSkipList) Get(key skiplist.K) (skiplist.V, bool) {
+func (sl *BasicSkipList[K, V]) Get(key K) (V, bool) {
 	cur := sl.find(key)
 	if cur != nil {
 		return cur.value, true
 	}
-	return 0, false
+	var zero V
+	return zero, false
 }
 
-func (sl *BasicSkipList) Contains(key skiplist.K) bool {
+func (sl *BasicSkipList[K, V]) Contains(key K) bool {
 	return sl.find(key) != nil
 }
 
-func (sl *BasicSkipList) Delete(key skiplist.K) {
+func (sl *BasicSkipList[K, V]) Delete(key K) (V, bool) {
+	var preds [maxLevel + 1]*basicNode[K, V]
 	curr := sl.head
 	for h := sl.level; h >= 0; h-- {
-		for curr.next[h] != nil && curr.next[h].key < key {
+		for curr.next[h] != nil && sl.compare(curr.next[h].key, key) < 0 {
 			curr = curr.next[h]
 		}
-		if curr.next[h] != nil && curr.next[h].key == key {
-			curr.next[h] = curr.next[h].next[h]
+		preds[h] = curr
+	}
+	target := curr.next[0]
+	if target == nil || sl.compare(target.key, key) != 0 {
+		var zero V
+		return zero, false
+	}
+	for h := int32(0); h <= sl.level; h++ {
+		if preds[h].next[h] == target {
+			preds[h].span[h] += target.span[h] - 1
+			preds[h].next[h] = target.next[h]
+		} else {
+			preds[h].span[h]--
 		}
 	}
 	sl.size--
+	return target.value, true
+}
+
+func (sl *BasicSkipList[K, V]) Len() int {
+	return int(sl.size)
 }
 
-func (sl *BasicSkipList) GetHead() skiplist.Nodelike {
+func (sl *BasicSkipList[K, V]) GetHead() skiplist.NodelikeOf[K, V] {
 	return sl.head
 }
 
-func (sl *BasicSkipList) GetMaxStats() (int, int) {
+func (sl *BasicSkipList[K, V]) GetMaxStats() (int, int) {
 	return int(sl.size), int(sl.level)
 }
 
-func (nd *basicNode) GetKey() skiplist.K {
+func (sl *BasicSkipList[K, V]) Compare(a, b K) int {
+	return sl.compare(a, b)
+}
+
+func (nd *basicNode[K, V]) GetKey() K {
 	return nd.key
 }
 
-func (nd *basicNode) GetValue() skiplist.V {
+func (nd *basicNode[K, V]) GetValue() V {
 	return nd.value
 }
 
-func (nd *basicNode) GetLevel() int32 {
+func (nd *basicNode[K, V]) GetLevel() int32 {
 	return int32(len(nd.next) - 1)
 }
 
-func (nd *basicNode) GetNextAt(level int32) skiplist.Nodelike {
+func (nd *basicNode[K, V]) GetNextAt(level int32) skiplist.NodelikeOf[K, V] {
 	if level < 0 || level >= int32(len(nd.next)) {
 		return nil
 	}
@@ -138,3 +208,8 @@ func (nd *basicNode) GetNextAt(level int32) skiplist.Nodelike {
 	}
 	return nd.next[level]
 }
+
+// NextAt 回傳第 h 層的下一個節點，供 skiplist.LevelIterator 走訪
+func (nd *basicNode[K, V]) NextAt(h int32) *basicNode[K, V] {
+	return nd.next[h]
+}
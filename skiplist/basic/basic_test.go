@@ -1,17 +1,56 @@
 package basic
 
 import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"math/rand"
 	"testing"
 
 	"github.com/Hakuto4838/SkipList.git/datastream"
 	"github.com/Hakuto4838/SkipList.git/skiplist"
 	"github.com/Hakuto4838/SkipList.git/skiplist/analyTool"
+	"github.com/Hakuto4838/SkipList.git/skiplist/skiplisttest"
 )
 
 func TestBasicSkipListInterface(t *testing.T) {
-	var _ skiplist.SkipList = (*BasicSkipList)(nil)
-	var _ skiplist.Analyable = (*BasicSkipList)(nil)
-	var _ skiplist.Nodelike = (*basicNode)(nil)
+	var _ skiplist.SkipList = (*BasicSkipList[skiplist.K, skiplist.V])(nil)
+	var _ skiplist.Analyable = (*BasicSkipList[skiplist.K, skiplist.V])(nil)
+	var _ skiplist.Nodelike = (*basicNode[skiplist.K, skiplist.V])(nil)
+	var _ skiplist.AnalyableOf[string, []int] = (*BasicSkipList[string, []int])(nil)
+	var _ skiplist.AnalyableOf[[]byte, []byte] = (*BasicSkipList[[]byte, []byte])(nil)
+}
+
+func TestBasicSkipListGeneric(t *testing.T) {
+	type record struct {
+		name  string
+		score int
+	}
+	sl := NewBasicSkipListOf[string, record](42)
+	words := []string{"pear", "apple", "fig", "banana", "cherry"}
+	for i, w := range words {
+		sl.Put(w, record{name: w, score: i})
+	}
+
+	for i, w := range words {
+		if r, found := sl.Get(w); !found || r.score != i {
+			t.Errorf("Get(%q) = (%v, %v), want score %d", w, r, found, i)
+		}
+	}
+
+	sl.Delete("fig")
+	if sl.Contains("fig") {
+		t.Error("Contains(fig) = true after delete, want false")
+	}
+
+	// level 0 需依字典序排列
+	prev := ""
+	for nd := sl.GetHead().GetNextAt(0); nd != nil; nd = nd.GetNextAt(0) {
+		if nd.GetKey() <= prev {
+			t.Errorf("keys out of order: %q after %q", nd.GetKey(), prev)
+		}
+		prev = nd.GetKey()
+	}
 }
 
 func TestBasicSkipListBasic(t *testing.T) {
@@ -23,6 +62,64 @@ func TestBasicSkipListBasic(t *testing.T) {
 	analyTool.PrintSkipList(sl, 5, 10)
 }
 
+func TestBasicSkipListBytesKey(t *testing.T) {
+	const N = 200
+	bytesSL := NewBasicSkipListFunc[[]byte, int](42, bytes.Compare)
+	intSL := NewBasicSkipList(42)
+	// big-endian 編碼讓 []byte 的字典序與整數順序一致，相同 seed 下兩者結構相同
+	keyOf := func(i int) []byte {
+		return binary.BigEndian.AppendUint64(nil, uint64(i))
+	}
+	for i := N - 1; i >= 0; i-- {
+		bytesSL.Put(keyOf(i), i)
+		intSL.Put(skiplist.K(i), skiplist.V(i))
+	}
+
+	for i := 0; i < N; i++ {
+		if value, found := bytesSL.Get(keyOf(i)); !found || value != i {
+			t.Fatalf("Get(%x) = (%d, %v), want (%d, true)", keyOf(i), value, found, i)
+		}
+		got, _ := analyTool.FindStepOf(bytesSL, keyOf(i))
+		want, _ := analyTool.FindStep(intSL, skiplist.K(i))
+		if got != want {
+			t.Errorf("FindStepOf(%x) = %d, want %d", keyOf(i), got, want)
+		}
+	}
+
+	bytesSL.Delete(keyOf(7))
+	if bytesSL.Contains(keyOf(7)) {
+		t.Error("Contains after delete = true, want false")
+	}
+}
+
+func TestBasicSkipListCompositeKey(t *testing.T) {
+	type tuple struct {
+		user string
+		seq  int
+	}
+	// 依 user 升冪、seq 降冪排序
+	compare := func(a, b tuple) int {
+		return cmp.Or(cmp.Compare(a.user, b.user), cmp.Compare(b.seq, a.seq))
+	}
+	sl := NewBasicSkipListFunc[tuple, string](42, compare)
+	sl.Put(tuple{"bob", 1}, "b1")
+	sl.Put(tuple{"alice", 1}, "a1")
+	sl.Put(tuple{"bob", 2}, "b2")
+	sl.Put(tuple{"alice", 3}, "a3")
+
+	want := []string{"a3", "a1", "b2", "b1"}
+	i := 0
+	for nd := sl.GetHead().GetNextAt(0); nd != nil; nd = nd.GetNextAt(0) {
+		if i >= len(want) || nd.GetValue() != want[i] {
+			t.Fatalf("order[%d] = %s, want %v", i, nd.GetValue(), want)
+		}
+		i++
+	}
+	if i != len(want) {
+		t.Errorf("got %d nodes, want %d", i, len(want))
+	}
+}
+
 func TestBigZipf(t *testing.T) {
 	data := datastream.NewZipfDataGenerator(100000, 1.5, 1, 42)
 	sl := NewBasicSkipList(42)
@@ -34,3 +131,59 @@ func TestBigZipf(t *testing.T) {
 	}
 	analyTool.PrintSkipList(sl, 5, 10)
 }
+
+func TestBasicSkipListConformance(t *testing.T) {
+	skiplisttest.Run(t, func() skiplist.SkipList { return NewBasicSkipList(42) })
+}
+
+func TestBasicSkipListRankSelect(t *testing.T) {
+	var _ skiplist.Indexable = NewBasicSkipList(42)
+	sl := NewBasicSkipList(42)
+	r := rand.New(rand.NewSource(7))
+	live := map[skiplist.K]bool{}
+	for op := 0; op < 3000; op++ {
+		key := skiplist.K(r.Intn(500))
+		if r.Intn(3) == 0 {
+			sl.Delete(key)
+			delete(live, key)
+		} else {
+			sl.Put(key, skiplist.V(key))
+			live[key] = true
+		}
+	}
+	check := func() {
+		rank := 0
+		for key := skiplist.K(-1); key <= 500; key++ {
+			got, found := sl.Rank(key)
+			if got != rank || found != live[key] {
+				t.Fatalf("Rank(%d) = (%d, %v), want (%d, %v)", key, got, found, rank, live[key])
+			}
+			if live[key] {
+				k, v, ok := sl.Select(rank)
+				if !ok || k != key || v != skiplist.V(key) {
+					t.Fatalf("Select(%d) = (%d, %f, %v), want (%d, %d, true)", rank, k, v, ok, key, key)
+				}
+				rank++
+			}
+		}
+		if _, _, ok := sl.Select(rank); ok {
+			t.Errorf("Select(%d) ok with %d elements, want false", rank, rank)
+		}
+		if _, _, ok := sl.Select(-1); ok {
+			t.Error("Select(-1) ok, want false")
+		}
+	}
+	check()
+
+	// 全部刪除再插入，確認層級縮減後 span 仍正確
+	for key := range live {
+		sl.Delete(key)
+	}
+	clear(live)
+	for i := 0; i < 50; i++ {
+		key := skiplist.K(r.Intn(500))
+		sl.Put(key, skiplist.V(key))
+		live[key] = true
+	}
+	check()
+}
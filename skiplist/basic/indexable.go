@@ -0,0 +1,37 @@
+package basic
+
+// Rank 回傳小於 key 的元素個數，即 key 以 0 起算的排名；key 存在時第二個回傳值為 true
+func (sl *BasicSkipList[K, V]) Rank(key K) (int, bool) {
+	rank := int32(0)
+	cur := sl.head
+	for h := sl.level; h >= 0; h-- {
+		for cur.next[h] != nil && sl.compare(cur.next[h].key, key) < 0 {
+			rank += cur.span[h]
+			cur = cur.next[h]
+		}
+	}
+	found := cur.next[0] != nil && sl.compare(cur.next[0].key, key) == 0
+	return int(rank), found
+}
+
+// Select 回傳第 i 小（0 起算）的元素，i 超出範圍時第三個回傳值為 false
+func (sl *BasicSkipList[K, V]) Select(i int) (K, V, bool) {
+	if i < 0 || i >= int(sl.size) {
+		var k K
+		var v V
+		return k, v, false
+	}
+	target := int32(i) + 1 // head 的排名為 0
+	traversed := int32(0)
+	cur := sl.head
+	for h := sl.level; h >= 0; h-- {
+		for cur.next[h] != nil && traversed+cur.span[h] <= target {
+			traversed += cur.span[h]
+			cur = cur.next[h]
+		}
+		if traversed == target {
+			break
+		}
+	}
+	return cur.key, cur.value, true
+}
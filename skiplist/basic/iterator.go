@@ -0,0 +1,55 @@
+package basic
+
+import (
+	"iter"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+)
+
+func (sl *BasicSkipList[K, V]) Iterator() skiplist.IteratorOf[K, V] {
+	return skiplist.NewLevelIterator[K, V](sl.head, &sl.level, sl.compare)
+}
+
+func (sl *BasicSkipList[K, V]) Range(lo, hi K, fn func(key K, value V) bool) {
+	skiplist.RangeOf(sl.Iterator(), sl.compare, lo, hi, fn)
+}
+
+func (sl *BasicSkipList[K, V]) All() iter.Seq2[K, V] {
+	return skiplist.AllOf(sl.Iterator)
+}
+
+func (sl *BasicSkipList[K, V]) Backward() iter.Seq2[K, V] {
+	return skiplist.BackwardOf(sl.Iterator)
+}
+
+func (sl *BasicSkipList[K, V]) Floor(key K) (K, V, bool) {
+	return skiplist.FloorOf(sl.Iterator(), sl.compare, key)
+}
+
+func (sl *BasicSkipList[K, V]) Ceiling(key K) (K, V, bool) {
+	return skiplist.CeilingOf(sl.Iterator(), key)
+}
+
+func (sl *BasicSkipList[K, V]) Predecessor(key K) (K, V, bool) {
+	return skiplist.PredecessorOf(sl.Iterator(), key)
+}
+
+func (sl *BasicSkipList[K, V]) Successor(key K) (K, V, bool) {
+	return skiplist.SuccessorOf(sl.Iterator(), sl.compare, key)
+}
+
+func (sl *BasicSkipList[K, V]) Min() (K, V, bool) {
+	return skiplist.MinOf(sl.Iterator())
+}
+
+func (sl *BasicSkipList[K, V]) Max() (K, V, bool) {
+	return skiplist.MaxOf(sl.Iterator())
+}
+
+func (sl *BasicSkipList[K, V]) PopMin() (K, V, bool) {
+	return skiplist.PopOf(sl.Min, sl.Delete)
+}
+
+func (sl *BasicSkipList[K, V]) PopMax() (K, V, bool) {
+	return skiplist.PopOf(sl.Max, sl.Delete)
+}
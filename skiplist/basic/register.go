@@ -0,0 +1,17 @@
+package basic
+
+import (
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/registry"
+)
+
+func init() {
+	registry.Register(registry.Impl{
+		Name:       "basic",
+		Usage:      "隨機層數的標準 skip list",
+		SharedRead: true,
+		New: func(p registry.Params, env registry.Env) (skiplist.SkipList, error) {
+			return NewBasicSkipList(env.Seed), nil
+		},
+	})
+}
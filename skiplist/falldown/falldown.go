@@ -0,0 +1,242 @@
+package falldown
+
+import (
+	"cmp"
+	"math/bits"
+	"math/rand"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+)
+
+const (
+	maxLevel    = 32
+	probability = 0.5
+)
+
+type fdNode struct {
+	key   skiplist.K
+	value skiplist.V
+	base  int32 // 插入時擲出的隨機高度，節點不會掉到此高度以下
+	last  int64 // 最近一次被存取或掉落時的時鐘
+	next  []*fdNode
+}
+
+// FdList 讓冷門節點隨時間掉落的 skip list。
+// 每次存取讓節點沿搜尋路徑上升一層（上限為 log2(size)）；
+// 搜尋途經的節點若閒置超過 size/2^lift 次操作（lift 為高出隨機高度的層數），
+// 就從頂層掉落一層，直到回到插入時的隨機高度。
+// 因此節點能維持的 lift 約為 log2(size * 存取頻率)，與 la 以預測頻率決定的高度相當。
+type FdList struct {
+	head  *fdNode
+	level int32
+	size  int32
+	clock int64     // 操作計數器
+	preds []*fdNode // 搜尋路徑上每層最後一個小於 key 的節點
+}
+
+func newNode(key skiplist.K, value skiplist.V, level int32) *fdNode {
+	return &fdNode{
+		key:   key,
+		value: value,
+		next:  make([]*fdNode, level+1),
+	}
+}
+
+func NewFdList() *FdList {
+	return &FdList{
+		head:  newNode(-1, 0, maxLevel),
+		level: 1,
+		preds: make([]*fdNode, maxLevel+1),
+	}
+}
+
+func (sl *FdList) randomLevel() int32 {
+	lvl := 0
+	for rand.Float64() < probability && lvl < maxLevel {
+		lvl++
+	}
+	return int32(lvl)
+}
+
+// idle 判斷節點是否已閒置超過 size/2^lift 次操作，越高的節點需要越頻繁的存取才能維持
+func (sl *FdList) idle(nd *fdNode) bool {
+	lift := int32(len(nd.next)-1) - nd.base
+	return sl.clock-nd.last > int64(sl.size)>>lift
+}
+
+// travel 由上而下搜尋 key，找到即提早返回。
+// 途經且頂層即為目前層的閒置節點會掉落一層，最高層因此清空時一併降低 sl.level；
+// 每層最後一個小於 key 的節點記錄在 sl.preds。
+func (sl *FdList) travel(key skiplist.K) *fdNode {
+	cur := sl.head
+	var found *fdNode
+	demoted := false
+	for h := sl.level; h >= 0; h-- {
+		for {
+			succ := cur.next[h]
+			if succ == nil || succ.key >= key {
+				break
+			}
+			if int32(len(succ.next)-1) == h && h > succ.base && sl.idle(succ) {
+				cur.next[h] = succ.next[h]
+				succ.next = succ.next[:h]
+				demoted = true
+				succ.last = sl.clock
+				continue
+			}
+			cur = succ
+		}
+		sl.preds[h] = cur
+		if cur.next[h] != nil && cur.next[h].key == key {
+			found = cur.next[h]
+			break
+		}
+	}
+	if demoted {
+		sl.shrinkLevel()
+	}
+	return found
+}
+
+// shrinkLevel 在最高層沒有節點後降低 sl.level
+func (sl *FdList) shrinkLevel() {
+	for sl.level > 1 && sl.head.next[sl.level] == nil {
+		sl.level--
+	}
+}
+
+// search 不觸發掉落的完整搜尋，記錄所有層的 preds，供插入與刪除使用
+func (sl *FdList) search(key skiplist.K) *fdNode {
+	cur := sl.head
+	for h := sl.level; h >= 0; h-- {
+		for cur.next[h] != nil && cur.next[h].key < key {
+			cur = cur.next[h]
+		}
+		sl.preds[h] = cur
+	}
+	if cur.next[0] != nil && cur.next[0].key == key {
+		return cur.next[0]
+	}
+	return nil
+}
+
+// climb 記錄一次存取並讓節點上升一層，呼叫前 sl.preds 需由 travel 記錄到節點頂層之上
+func (sl *FdList) climb(nd *fdNode) {
+	nd.last = sl.clock
+	top := int32(len(nd.next) - 1)
+	if top >= maxLevel || top >= int32(bits.Len32(uint32(sl.size))) {
+		return
+	}
+	top++
+	pred := sl.head
+	if top <= sl.level {
+		pred = sl.preds[top]
+	}
+	nd.next = append(nd.next, pred.next[top])
+	pred.next[top] = nd
+	sl.level = max(sl.level, top)
+}
+
+func (sl *FdList) Put(key skiplist.K, value skiplist.V) (skiplist.V, bool) {
+	sl.clock++
+	if nd := sl.travel(key); nd != nil {
+		old := nd.value
+		nd.value = value
+		sl.climb(nd)
+		return old, true
+	}
+	lvl := sl.randomLevel()
+	nd := newNode(key, value, lvl)
+	nd.base = lvl
+	nd.last = sl.clock
+	sl.size++
+
+	sl.search(key)
+	for h := int32(0); h <= lvl; h++ {
+		pred := sl.head
+		if h <= sl.level {
+			pred = sl.preds[h]
+		}
+		nd.next[h] = pred.next[h]
+		pred.next[h] = nd
+	}
+	sl.level = max(sl.level, lvl)
+	return 0, false
+}
+
+func (sl *FdList) Get(key skiplist.K) (skiplist.V, bool) {
+	sl.clock++
+	nd := sl.travel(key)
+	if nd == nil {
+		return 0, false
+	}
+	sl.climb(nd)
+	return nd.value, true
+}
+
+func (sl *FdList) Contains(key skiplist.K) bool {
+	sl.clock++
+	nd := sl.travel(key)
+	if nd == nil {
+		return false
+	}
+	sl.climb(nd)
+	return true
+}
+
+func (sl *FdList) Delete(key skiplist.K) (skiplist.V, bool) {
+	sl.clock++
+	nd := sl.search(key)
+	if nd == nil {
+		return 0, false
+	}
+	for h := int32(len(nd.next) - 1); h >= 0; h-- {
+		sl.preds[h].next[h] = nd.next[h]
+	}
+	sl.size--
+	sl.shrinkLevel()
+	return nd.value, true
+}
+
+func (sl *FdList) Len() int {
+	return int(sl.size)
+}
+
+func (sl *FdList) GetHead() skiplist.Nodelike {
+	return sl.head
+}
+
+func (sl *FdList) GetMaxStats() (int, int) {
+	return int(sl.size), int(sl.level)
+}
+
+func (sl *FdList) Compare(a, b skiplist.K) int {
+	return cmp.Compare(a, b)
+}
+
+func (nd *fdNode) GetKey() skiplist.K {
+	return nd.key
+}
+
+func (nd *fdNode) GetValue() skiplist.V {
+	return nd.value
+}
+
+func (nd *fdNode) GetLevel() int32 {
+	return int32(len(nd.next) - 1)
+}
+
+func (nd *fdNode) GetNextAt(level int32) skiplist.Nodelike {
+	if level < 0 || level >= int32(len(nd.next)) {
+		return nil
+	}
+	if nd.next[level] == nil {
+		return nil
+	}
+	return nd.next[level]
+}
+
+// NextAt 回傳第 h 層的下一個節點，供 skiplist.LevelIterator 走訪
+func (nd *fdNode) NextAt(h int32) *fdNode {
+	return nd.next[h]
+}
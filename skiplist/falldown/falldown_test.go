@@ -0,0 +1,115 @@
+package falldown
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Hakuto4838/SkipList.git/datastream"
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/analyTool"
+	"github.com/Hakuto4838/SkipList.git/skiplist/skiplisttest"
+)
+
+func TestFdListInterface(t *testing.T) {
+	var _ skiplist.SkipList = (*FdList)(nil)
+	var _ skiplist.Analyable = (*FdList)(nil)
+	var _ skiplist.Nodelike = (*fdNode)(nil)
+}
+
+func TestFdListBasic(t *testing.T) {
+	sl := NewFdList()
+	for i := 0; i < 100; i++ {
+		sl.Put(skiplist.K(i), skiplist.V(i*10))
+	}
+
+	for i := 0; i < 100; i++ {
+		if value, found := sl.Get(skiplist.K(i)); !found || value != skiplist.V(i*10) {
+			t.Errorf("Get(%d) = (%f, %v), want (%d, true)", i, value, found, i*10)
+		}
+	}
+
+	for i := 0; i < 100; i += 2 {
+		sl.Delete(skiplist.K(i))
+	}
+	for i := 0; i < 100; i++ {
+		if sl.Contains(skiplist.K(i)) != (i%2 == 1) {
+			t.Errorf("Contains(%d) = %v after delete", i, !(i%2 == 1))
+		}
+	}
+
+	if size, _ := sl.GetMaxStats(); size != 50 {
+		t.Errorf("size = %d, want 50", size)
+	}
+	if !analyTool.CheckStruct(sl) {
+		t.Error("CheckStruct failed")
+	}
+}
+
+func TestFdListFalldown(t *testing.T) {
+	const N = 64
+	sl := NewFdList()
+	for i := 0; i < N; i++ {
+		sl.Put(skiplist.K(2*i), skiplist.V(i))
+	}
+	// 取基底高度為 0 的節點，避免基底已達上升上限
+	hot := sl.head.next[0]
+	for hot.base > 0 {
+		hot = hot.next[0]
+	}
+
+	// 熱門節點持續上升
+	for range 100 {
+		sl.Get(hot.key)
+	}
+	if hot.GetLevel() <= hot.base {
+		t.Fatalf("hot node level = %d, base = %d, want climbed", hot.GetLevel(), hot.base)
+	}
+	analyTool.PrintSkipList(sl, 8, 10)
+
+	// 不再存取後，經過它的搜尋會讓它逐層掉回基底高度
+	for range N * maxLevel {
+		sl.Get(hot.key + 1)
+	}
+	if hot.GetLevel() != hot.base {
+		t.Errorf("idle node level = %d, want base %d", hot.GetLevel(), hot.base)
+	}
+	if _, lvl := sl.GetMaxStats(); lvl != usedLevel(sl) {
+		t.Errorf("GetMaxStats level = %d after falling, want %d", lvl, usedLevel(sl))
+	}
+	if !analyTool.CheckStruct(sl) {
+		t.Error("CheckStruct failed")
+	}
+	analyTool.PrintSkipList(sl, 8, 10)
+}
+
+func TestFdListZipf(t *testing.T) {
+	const N = 1000
+	data := datastream.NewZipfDataGenerator(N, 1.5, 1, 42)
+	keymap := data.GetKeyMap()
+
+	sl := NewFdList()
+	for k, v := range keymap {
+		sl.Put(k, v)
+	}
+	for range N * 10 {
+		sl.Get(skiplist.K(data.Next()))
+	}
+	score, _ := analyTool.AnalyzeStep(sl, keymap)
+	fmt.Printf("score: %f\n", score)
+	if !analyTool.CheckStruct(sl) {
+		t.Error("CheckStruct failed")
+	}
+}
+
+func TestFdListConformance(t *testing.T) {
+	skiplisttest.Run(t, func() skiplist.SkipList { return NewFdList() })
+}
+
+// usedLevel 回傳頭節點實際有後繼的最高層，至少為 1
+func usedLevel(sl *FdList) int {
+	h := maxLevel
+	for h > 1 && sl.head.next[h] == nil {
+		h--
+	}
+	return h
+}
@@ -0,0 +1,16 @@
+package falldown
+
+import (
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/registry"
+)
+
+func init() {
+	registry.Register(registry.Impl{
+		Name:  "falldown",
+		Usage: "讓冷門節點隨時間掉落的 skip list",
+		New: func(p registry.Params, env registry.Env) (skiplist.SkipList, error) {
+			return NewFdList(), nil
+		},
+	})
+}
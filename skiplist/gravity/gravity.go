@@ -0,0 +1,270 @@
+package gravity
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+)
+
+const (
+	maxLevel    = 32
+	probability = 0.5
+
+	defaultZ            = 1.8
+	defaultTryThreshold = 0.5
+)
+
+type gNode struct {
+	key   skiplist.K
+	value skiplist.V
+	hits  int64 // 存取次數，即節點的「質量」
+	base  int32 // 插入時擲出的隨機高度，重力不會讓節點低於此高度
+	next  []*gNode
+}
+
+// GravityList 以存取頻率決定浮力的 skip list。
+// 節點的相對質量 np = size * hits / m，在隨機基底高度之上再浮升 lift 層，
+// 穩定區間為 z^(lift-tryThreshold) <= np < z^(lift+1-tryThreshold)。
+// 被存取的節點會在路徑上向上浮升；搜尋途經的輕節點則受重力影響下沉一層。
+type GravityList struct {
+	head         *gNode
+	level        int32
+	size         int32
+	m            int64 // 總存取次數
+	z            float64
+	tryThreshold float64
+	lift         []float64 // lift[i] = z^(i-tryThreshold)，浮升 i 層所需的 np
+	preds        []*gNode  // 搜尋路徑上每層最後一個小於 key 的節點
+}
+
+func newNode(key skiplist.K, value skiplist.V, level int32) *gNode {
+	return &gNode{
+		key:   key,
+		value: value,
+		next:  make([]*gNode, level+1),
+	}
+}
+
+// NewGravityList 以預設參數 (z=1.8, tryThreshold=0.5) 建立 GravityList
+func NewGravityList() *GravityList {
+	return NewGravityListWithParams(defaultZ, defaultTryThreshold)
+}
+
+// NewGravityListWithParams 建立 GravityList
+// z: 相鄰浮升層之間的質量倍率，需大於 1
+// tryThreshold: 介於 0 與 1，越大越容易浮升、越不容易下沉
+// z 不大於 1 時浮升門檻不會隨層數增加，會 panic
+func NewGravityListWithParams(z, tryThreshold float64) *GravityList {
+	if !(z > 1) {
+		panic(fmt.Sprintf("gravity: z must be > 1, got %g", z))
+	}
+	lift := make([]float64, maxLevel+2)
+	for i := range lift {
+		lift[i] = math.Pow(z, float64(i)-tryThreshold)
+	}
+	return &GravityList{
+		head:         newNode(-1, 0, maxLevel),
+		level:        1,
+		z:            z,
+		tryThreshold: tryThreshold,
+		lift:         lift,
+		preds:        make([]*gNode, maxLevel+1),
+	}
+}
+
+func (sl *GravityList) randomLevel() int32 {
+	lvl := 0
+	for rand.Float64() < probability && lvl < maxLevel {
+		lvl++
+	}
+	return int32(lvl)
+}
+
+// heavierThan 判斷節點的 np 是否達到 lift[i]
+func (sl *GravityList) heavierThan(nd *gNode, i int32) bool {
+	return float64(sl.size)*float64(nd.hits) >= float64(sl.m)*sl.lift[i]
+}
+
+// travel 由上而下搜尋 key，找到即提早返回。
+// 途經且頂層即為目前層的節點若質量不足以支撐其浮升高度，會被移出該層，最高層因此清空時一併降低 sl.level；
+// 每層最後一個小於 key 的節點記錄在 sl.preds。
+func (sl *GravityList) travel(key skiplist.K) *gNode {
+	cur := sl.head
+	var found *gNode
+	demoted := false
+	for h := sl.level; h >= 0; h-- {
+		for {
+			succ := cur.next[h]
+			if succ == nil || succ.key >= key {
+				break
+			}
+			top := int32(len(succ.next) - 1)
+			if top == h && top > succ.base && !sl.heavierThan(succ, top-succ.base) {
+				// 重力：移出第 h 層後留在原地，繼續檢查新的後繼
+				cur.next[h] = succ.next[h]
+				succ.next = succ.next[:h]
+				demoted = true
+				continue
+			}
+			cur = succ
+		}
+		sl.preds[h] = cur
+		if cur.next[h] != nil && cur.next[h].key == key {
+			found = cur.next[h]
+			break
+		}
+	}
+	if demoted {
+		sl.shrinkLevel()
+	}
+	return found
+}
+
+// shrinkLevel 在最高層沒有節點後降低 sl.level
+func (sl *GravityList) shrinkLevel() {
+	for sl.level > 1 && sl.head.next[sl.level] == nil {
+		sl.level--
+	}
+}
+
+// search 不套用重力的完整搜尋，記錄所有層的 preds，供插入與刪除使用
+func (sl *GravityList) search(key skiplist.K) *gNode {
+	cur := sl.head
+	for h := sl.level; h >= 0; h-- {
+		for cur.next[h] != nil && cur.next[h].key < key {
+			cur = cur.next[h]
+		}
+		sl.preds[h] = cur
+	}
+	if cur.next[0] != nil && cur.next[0].key == key {
+		return cur.next[0]
+	}
+	return nil
+}
+
+// float 記錄一次存取，並讓節點沿著剛走過的路徑向上浮升。
+// 呼叫前 sl.preds 需由 travel 記錄到節點頂層之上。
+func (sl *GravityList) float(nd *gNode) {
+	nd.hits++
+	sl.m++
+	top := int32(len(nd.next) - 1)
+	for top < maxLevel && sl.heavierThan(nd, top+1-nd.base) {
+		top++
+		pred := sl.head
+		if top <= sl.level {
+			pred = sl.preds[top]
+		}
+		nd.next = append(nd.next, pred.next[top])
+		pred.next[top] = nd
+	}
+	sl.level = max(sl.level, top)
+}
+
+func (sl *GravityList) Put(key skiplist.K, value skiplist.V) (skiplist.V, bool) {
+	if nd := sl.travel(key); nd != nil {
+		old := nd.value
+		nd.value = value
+		sl.float(nd)
+		return old, true
+	}
+	lvl := sl.randomLevel()
+	nd := newNode(key, value, lvl)
+	nd.base = lvl
+	nd.hits = 1
+	sl.m++
+	sl.size++
+
+	sl.search(key)
+	for h := int32(0); h <= lvl; h++ {
+		pred := sl.head
+		if h <= sl.level {
+			pred = sl.preds[h]
+		}
+		nd.next[h] = pred.next[h]
+		pred.next[h] = nd
+	}
+	sl.level = max(sl.level, lvl)
+	return 0, false
+}
+
+func (sl *GravityList) Get(key skiplist.K) (skiplist.V, bool) {
+	nd := sl.travel(key)
+	if nd == nil {
+		return 0, false
+	}
+	sl.float(nd)
+	return nd.value, true
+}
+
+func (sl *GravityList) Contains(key skiplist.K) bool {
+	nd := sl.travel(key)
+	if nd == nil {
+		return false
+	}
+	sl.float(nd)
+	return true
+}
+
+func (sl *GravityList) Delete(key skiplist.K) (skiplist.V, bool) {
+	nd := sl.search(key)
+	if nd == nil {
+		return 0, false
+	}
+	for h := int32(len(nd.next) - 1); h >= 0; h-- {
+		sl.preds[h].next[h] = nd.next[h]
+	}
+	sl.size--
+	sl.shrinkLevel()
+	return nd.value, true
+}
+
+// GetParams 回傳目前使用的 z 與 tryThreshold
+func (sl *GravityList) GetParams() (z, tryThreshold float64) {
+	return sl.z, sl.tryThreshold
+}
+
+func (sl *GravityList) Len() int {
+	return int(sl.size)
+}
+
+func (sl *GravityList) GetHead() skiplist.Nodelike {
+	return sl.head
+}
+
+func (sl *GravityList) GetMaxStats() (int, int) {
+	return int(sl.size), int(sl.level)
+}
+
+func (sl *GravityList) Compare(a, b skiplist.K) int {
+	return cmp.Compare(a, b)
+}
+
+func (nd *gNode) GetKey() skiplist.K {
+	return nd.key
+}
+
+func (nd *gNode) GetValue() skiplist.V {
+	return nd.value
+}
+
+func (nd *gNode) GetLevel() int32 {
+	return int32(len(nd.next) - 1)
+}
+
+func (nd *gNode) GetNextAt(level int32) skiplist.Nodelike {
+	if level < 0 || level >= int32(len(nd.next)) {
+		return nil
+	}
+	if nd.next[level] == nil {
+		return nil
+	}
+	return nd.next[level]
+}
+
+// NextAt 回傳第 h 層的下一個節點，供 skiplist.LevelIterator 走訪
+func (nd *gNode) NextAt(h int32) *gNode {
+	return nd.next[h]
+}
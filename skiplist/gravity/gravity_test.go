@@ -0,0 +1,132 @@
+package gravity
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/Hakuto4838/SkipList.git/datastream"
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/analyTool"
+	"github.com/Hakuto4838/SkipList.git/skiplist/skiplisttest"
+)
+
+func TestGravityListInterface(t *testing.T) {
+	var _ skiplist.SkipList = (*GravityList)(nil)
+	var _ skiplist.Analyable = (*GravityList)(nil)
+	var _ skiplist.Nodelike = (*gNode)(nil)
+}
+
+func TestGravityListBasic(t *testing.T) {
+	sl := NewGravityList()
+	for i := 0; i < 100; i++ {
+		sl.Put(skiplist.K(i), skiplist.V(i*10))
+	}
+
+	for i := 0; i < 100; i++ {
+		if value, found := sl.Get(skiplist.K(i)); !found || value != skiplist.V(i*10) {
+			t.Errorf("Get(%d) = (%f, %v), want (%d, true)", i, value, found, i*10)
+		}
+	}
+
+	for i := 0; i < 100; i += 2 {
+		sl.Delete(skiplist.K(i))
+	}
+	for i := 0; i < 100; i++ {
+		if sl.Contains(skiplist.K(i)) != (i%2 == 1) {
+			t.Errorf("Contains(%d) = %v after delete", i, !(i%2 == 1))
+		}
+	}
+
+	if size, _ := sl.GetMaxStats(); size != 50 {
+		t.Errorf("size = %d, want 50", size)
+	}
+	if !analyTool.CheckStruct(sl) {
+		t.Error("CheckStruct failed")
+	}
+}
+
+func TestGravityListParams(t *testing.T) {
+	z, th := NewGravityList().GetParams()
+	if z != defaultZ || th != defaultTryThreshold {
+		t.Errorf("GetParams() = (%f, %f), want (%f, %f)", z, th, defaultZ, defaultTryThreshold)
+	}
+
+	const N = 1000
+	data := datastream.NewZipfDataGenerator(N, 1.5, 1, 42)
+	keymap := data.GetKeyMap()
+	seq := data.GenerateSequence(N * 10)
+
+	for _, params := range [][2]float64{{1.5, 0.2}, {1.8, 0.5}, {3.0, 0.9}} {
+		sl := NewGravityListWithParams(params[0], params[1])
+		for k, v := range keymap {
+			sl.Put(k, v)
+		}
+		for _, k := range seq {
+			sl.Get(skiplist.K(k))
+		}
+		if !analyTool.CheckStruct(sl) {
+			t.Fatalf("CheckStruct failed with z=%f, tryThreshold=%f", params[0], params[1])
+		}
+		score, _ := analyTool.AnalyzeStep(sl, keymap)
+		fmt.Printf("z=%.2f tryThreshold=%.2f score: %f\n", params[0], params[1], score)
+	}
+
+	for _, z := range []float64{1, 0.5, math.NaN()} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewGravityListWithParams(%g, 0.5) did not panic", z)
+				}
+			}()
+			NewGravityListWithParams(z, 0.5)
+		}()
+	}
+}
+
+func TestGravityListConformance(t *testing.T) {
+	skiplisttest.Run(t, func() skiplist.SkipList { return NewGravityList() })
+}
+
+// usedLevel 回傳頭節點實際有後繼的最高層，至少為 1
+func usedLevel(sl *GravityList) int {
+	h := maxLevel
+	for h > 1 && sl.head.next[h] == nil {
+		h--
+	}
+	return h
+}
+
+func TestGravityListSink(t *testing.T) {
+	const N = 256
+	sl := NewGravityList()
+	for i := 0; i < N; i++ {
+		sl.Put(skiplist.K(i), skiplist.V(i))
+	}
+	hot := sl.head.next[0]
+
+	// 只存取 0，使其浮升
+	for range N * 4 {
+		sl.Get(0)
+	}
+	high := hot.GetLevel()
+	if high <= hot.base {
+		t.Fatalf("hot node level = %d, base = %d, want floated", high, hot.base)
+	}
+
+	// 之後只存取其他 key，0 的質量不足而下沉，最高層隨之降低
+	for range 64 {
+		for i := 1; i < N; i++ {
+			sl.Get(skiplist.K(i))
+		}
+	}
+	if hot.GetLevel() >= high {
+		t.Fatalf("hot node level = %d, want below %d after sinking", hot.GetLevel(), high)
+	}
+	if _, lvl := sl.GetMaxStats(); lvl != usedLevel(sl) {
+		t.Errorf("GetMaxStats level = %d after sinking, want %d", lvl, usedLevel(sl))
+	}
+	if !analyTool.CheckStruct(sl) {
+		t.Error("CheckStruct failed")
+	}
+}
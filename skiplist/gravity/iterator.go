@@ -0,0 +1,56 @@
+package gravity
+
+import (
+	"cmp"
+	"iter"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+)
+
+func (sl *GravityList) Iterator() skiplist.IteratorOf[skiplist.K, skiplist.V] {
+	return skiplist.NewLevelIterator[skiplist.K, skiplist.V](sl.head, &sl.level, cmp.Compare[skiplist.K])
+}
+
+func (sl *GravityList) Range(lo, hi skiplist.K, fn func(key skiplist.K, value skiplist.V) bool) {
+	skiplist.RangeOf(sl.Iterator(), cmp.Compare[skiplist.K], lo, hi, fn)
+}
+
+func (sl *GravityList) All() iter.Seq2[skiplist.K, skiplist.V] {
+	return skiplist.AllOf(sl.Iterator)
+}
+
+func (sl *GravityList) Backward() iter.Seq2[skiplist.K, skiplist.V] {
+	return skiplist.BackwardOf(sl.Iterator)
+}
+
+func (sl *GravityList) Floor(key skiplist.K) (skiplist.K, skiplist.V, bool) {
+	return skiplist.FloorOf(sl.Iterator(), cmp.Compare[skiplist.K], key)
+}
+
+func (sl *GravityList) Ceiling(key skiplist.K) (skiplist.K, skiplist.V, bool) {
+	return skiplist.CeilingOf(sl.Iterator(), key)
+}
+
+func (sl *GravityList) Predecessor(key skiplist.K) (skiplist.K, skiplist.V, bool) {
+	return skiplist.PredecessorOf(sl.Iterator(), key)
+}
+
+func (sl *GravityList) Successor(key skiplist.K) (skiplist.K, skiplist.V, bool) {
+	return skiplist.SuccessorOf(sl.Iterator(), cmp.Compare[skiplist.K], key)
+}
+
+func (sl *GravityList) Min() (skiplist.K, skiplist.V, bool) {
+	return skiplist.MinOf(sl.Iterator())
+}
+
+func (sl *GravityList) Max() (skiplist.K, skiplist.V, bool) {
+	return skiplist.MaxOf(sl.Iterator())
+}
+
+func (sl *GravityList) PopMin() (skiplist.K, skiplist.V, bool) {
+	return skiplist.PopOf(sl.Min, sl.Delete)
+}
+
+func (sl *GravityList) PopMax() (skiplist.K, skiplist.V, bool) {
+	return skiplist.PopOf(sl.Max, sl.Delete)
+}
@@ -0,0 +1,29 @@
+package gravity
+
+import (
+	"fmt"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/registry"
+)
+
+func init() {
+	registry.Register(registry.Impl{
+		Name:  "gravity",
+		Usage: "依存取質量浮升與下沉的 skip list",
+		Params: []registry.Param{
+			{Name: "z", Kind: registry.Float, Default: "1.8", Usage: "相鄰浮升層之間的質量倍率，需大於 1"},
+			{Name: "threshold", Kind: registry.Float, Default: "0.5", Usage: "越大越容易浮升、越不容易下沉"},
+		},
+		New: func(p registry.Params, env registry.Env) (skiplist.SkipList, error) {
+			if z := p.Float("z"); !(z > 1) {
+				return nil, fmt.Errorf("z must be > 1, got %g", z)
+			}
+			th, err := p.Probability("threshold")
+			if err != nil {
+				return nil, err
+			}
+			return NewGravityListWithParams(p.Float("z"), th), nil
+		},
+	})
+}
@@ -1,26 +1,96 @@
 package skiplist
 
+import "iter"
+
 type K = int64
 type V = float64
 
-type SkipList interface {
+// SkipListOf 泛型版本的 skip list 介面
+type SkipListOf[K, V any] interface {
 	Contains(key K) bool
 	Get(key K) (V, bool)
-	Put(key K, value V)
-	Delete(key K)
-	GetHead() Nodelike
+	// Put 插入或更新 key；更新既有元素時回傳舊值與 true
+	Put(key K, value V) (old V, replaced bool)
+	// Delete 刪除 key；key 存在時回傳被刪除的值與 true
+	Delete(key K) (V, bool)
+	// Len 回傳目前的元素個數
+	Len() int
+	GetHead() NodelikeOf[K, V]
+	// Iterator 回傳尚未定位的迭代器，使用前需先呼叫 Seek 系列方法
+	Iterator() IteratorOf[K, V]
+	// Range 依序走訪 lo <= key < hi 的元素，fn 回傳 false 時停止
+	Range(lo, hi K, fn func(key K, value V) bool)
+	// All 依 key 升冪走訪所有元素
+	All() iter.Seq2[K, V]
+	// Backward 依 key 降冪走訪所有元素
+	Backward() iter.Seq2[K, V]
+	// Floor 回傳最大的 key <= 指定 key，splay 與 Tlist 會將回傳的元素視為一次存取
+	Floor(key K) (K, V, bool)
+	// Ceiling 回傳最小的 key >= 指定 key
+	Ceiling(key K) (K, V, bool)
+	// Predecessor 回傳最大的 key < 指定 key
+	Predecessor(key K) (K, V, bool)
+	// Successor 回傳最小的 key > 指定 key
+	Successor(key K) (K, V, bool)
+	// Min 與 Max 回傳最小與最大的元素，串列為空時第三個回傳值為 false
+	Min() (K, V, bool)
+	Max() (K, V, bool)
+	// PopMin 與 PopMax 移除並回傳最小與最大的元素，可將 skip list 當作優先佇列使用
+	PopMin() (K, V, bool)
+	PopMax() (K, V, bool)
+}
+
+// IteratorOf 依 key 順序走訪 skip list，會略過邏輯刪除的節點。
+// Key、Value、Next、Prev 只能在 Valid 為 true 時呼叫；
+// 走訪不會被視為存取，splay 與 Tlist 不會因此調整結構。
+type IteratorOf[K, V any] interface {
+	Valid() bool
+	Key() K
+	Value() V
+	// Next 移到下一個元素
+	Next()
+	// Prev 移到前一個元素，skip list 沒有反向指標，每次需重新搜尋，成本為 O(log n)
+	Prev()
+	// Seek 移到第一個 key >= 指定 key 的元素
+	Seek(key K)
+	// SeekBefore 移到最後一個 key < 指定 key 的元素
+	SeekBefore(key K)
+	SeekToFirst()
+	SeekToLast()
 }
 
-// Analyable 提供分析功能的介面
-type Analyable interface {
-	SkipList
+// AnalyableOf 提供分析功能的介面
+type AnalyableOf[K, V any] interface {
+	SkipListOf[K, V]
 	// GetMaxStats 獲取最大節點數和最大層級
 	GetMaxStats() (maxNodes int, maxLevel int)
+	// Compare 以 skip list 本身的順序比較兩個 key
+	Compare(a, b K) int
 }
 
-type Nodelike interface {
+// IndexableOf 支援順序統計的 skip list，Rank 與 Select 皆為 O(log n)
+type IndexableOf[K, V any] interface {
+	SkipListOf[K, V]
+	// Rank 回傳小於 key 的元素個數，即 key 以 0 起算的排名；key 存在時第二個回傳值為 true
+	Rank(key K) (int, bool)
+	// Select 回傳第 i 小（0 起算）的元素，i 超出範圍時第三個回傳值為 false
+	Select(i int) (K, V, bool)
+}
+
+type NodelikeOf[K, V any] interface {
 	GetKey() K
 	GetValue() V
 	GetLevel() int32
-	GetNextAt(level int32) Nodelike
+	GetNextAt(level int32) NodelikeOf[K, V]
 }
+
+// Comparator 比較 a 與 b：a < b 回傳負數、a == b 回傳 0、a > b 回傳正數
+type Comparator[K any] func(a, b K) int
+
+// 以 int64 key、float64 value 實例化的版本，供 analyTool 與 benchrun 使用
+type (
+	SkipList  = SkipListOf[K, V]
+	Analyable = AnalyableOf[K, V]
+	Indexable = IndexableOf[K, V]
+	Nodelike  = NodelikeOf[K, V]
+)
@@ -0,0 +1,190 @@
+package skiplist
+
+import "iter"
+
+// RangeOf 以 it 依序走訪 lo <= key < hi 的元素，fn 回傳 false 時停止
+func RangeOf[K, V any](it IteratorOf[K, V], compare func(a, b K) int, lo, hi K, fn func(key K, value V) bool) {
+	for it.Seek(lo); it.Valid() && compare(it.Key(), hi) < 0; it.Next() {
+		if !fn(it.Key(), it.Value()) {
+			return
+		}
+	}
+}
+
+// AllOf 以 newIter 建立的迭代器依 key 升冪走訪所有元素，每次 range 都會建立新的迭代器
+func AllOf[K, V any](newIter func() IteratorOf[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		it := newIter()
+		for it.SeekToFirst(); it.Valid(); it.Next() {
+			if !yield(it.Key(), it.Value()) {
+				return
+			}
+		}
+	}
+}
+
+// BackwardOf 以 newIter 建立的迭代器依 key 降冪走訪所有元素
+func BackwardOf[K, V any](newIter func() IteratorOf[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		it := newIter()
+		for it.SeekToLast(); it.Valid(); it.Prev() {
+			if !yield(it.Key(), it.Value()) {
+				return
+			}
+		}
+	}
+}
+
+// current 回傳 it 目前的元素，it 無效時回傳零值與 false
+func current[K, V any](it IteratorOf[K, V]) (K, V, bool) {
+	if !it.Valid() {
+		var k K
+		var v V
+		return k, v, false
+	}
+	return it.Key(), it.Value(), true
+}
+
+// FloorOf 以 it 找出最大的 key <= key，只需一次由上而下的搜尋
+func FloorOf[K, V any](it IteratorOf[K, V], compare func(a, b K) int, key K) (K, V, bool) {
+	it.SeekBefore(key)
+	k, v, ok := current(it)
+	if ok {
+		it.Next()
+	} else {
+		it.SeekToFirst()
+	}
+	if it.Valid() && compare(it.Key(), key) == 0 {
+		return current(it)
+	}
+	return k, v, ok
+}
+
+// CeilingOf 以 it 找出最小的 key >= key
+func CeilingOf[K, V any](it IteratorOf[K, V], key K) (K, V, bool) {
+	it.Seek(key)
+	return current(it)
+}
+
+// PredecessorOf 以 it 找出最大的 key < key
+func PredecessorOf[K, V any](it IteratorOf[K, V], key K) (K, V, bool) {
+	it.SeekBefore(key)
+	return current(it)
+}
+
+// SuccessorOf 以 it 找出最小的 key > key
+func SuccessorOf[K, V any](it IteratorOf[K, V], compare func(a, b K) int, key K) (K, V, bool) {
+	it.Seek(key)
+	if it.Valid() && compare(it.Key(), key) == 0 {
+		it.Next()
+	}
+	return current(it)
+}
+
+// MinOf 以 it 找出最小的元素
+func MinOf[K, V any](it IteratorOf[K, V]) (K, V, bool) {
+	it.SeekToFirst()
+	return current(it)
+}
+
+// MaxOf 以 it 找出最大的元素
+func MaxOf[K, V any](it IteratorOf[K, V]) (K, V, bool) {
+	it.SeekToLast()
+	return current(it)
+}
+
+// PopOf 以 peek 取得元素後以 del 將其刪除，供 PopMin 與 PopMax 共用
+func PopOf[K, V any](peek func() (K, V, bool), del func(K) (V, bool)) (K, V, bool) {
+	k, v, ok := peek()
+	if ok {
+		del(k)
+	}
+	return k, v, ok
+}
+
+// LevelNode 為以 next 指標串接的節點，N 為節點本身的指標型別，nil 表示串列尾端
+type LevelNode[K, V any, N comparable] interface {
+	comparable
+	GetKey() K
+	GetValue() V
+	// NextAt 回傳第 h 層的下一個節點
+	NextAt(h int32) N
+}
+
+// LevelIterator 為沒有刪除標記的 skip list 共用的迭代器：Next 沿第 0 層前進，
+// Seek、SeekBefore 與 Prev 由目前的最高層往下搜尋，不會改變結構
+type LevelIterator[K, V any, N LevelNode[K, V, N]] struct {
+	head    N
+	level   *int32 // 串列目前的最高層，每次搜尋時讀取
+	compare func(a, b K) int
+	cur     N
+}
+
+// NewLevelIterator 回傳走訪以 head 為頭節點之串列的迭代器，level 指向串列記錄最高層的欄位
+func NewLevelIterator[K, V any, N LevelNode[K, V, N]](head N, level *int32, compare func(a, b K) int) *LevelIterator[K, V, N] {
+	return &LevelIterator[K, V, N]{head: head, level: level, compare: compare}
+}
+
+// findLess 回傳最後一個 key 小於指定 key 的節點，沒有時回傳 head
+func (it *LevelIterator[K, V, N]) findLess(key K) N {
+	cur := it.head
+	for h := *it.level; h >= 0; h-- {
+		for next := cur.NextAt(h); next != it.zero() && it.compare(next.GetKey(), key) < 0; next = cur.NextAt(h) {
+			cur = next
+		}
+	}
+	return cur
+}
+
+// findLast 回傳最後一個節點，串列為空時回傳 head
+func (it *LevelIterator[K, V, N]) findLast() N {
+	cur := it.head
+	for h := *it.level; h >= 0; h-- {
+		for next := cur.NextAt(h); next != it.zero(); next = cur.NextAt(h) {
+			cur = next
+		}
+	}
+	return cur
+}
+
+// zero 回傳 N 的零值，即代表尾端的 nil 指標
+func (it *LevelIterator[K, V, N]) zero() N {
+	var zero N
+	return zero
+}
+
+// nodeOrNil 將 head 轉換為 nil，表示迭代器已越界
+func (it *LevelIterator[K, V, N]) nodeOrNil(nd N) N {
+	if nd == it.head {
+		return it.zero()
+	}
+	return nd
+}
+
+func (it *LevelIterator[K, V, N]) Valid() bool { return it.cur != it.zero() }
+func (it *LevelIterator[K, V, N]) Key() K      { return it.cur.GetKey() }
+func (it *LevelIterator[K, V, N]) Value() V    { return it.cur.GetValue() }
+
+func (it *LevelIterator[K, V, N]) Next() {
+	it.cur = it.cur.NextAt(0)
+}
+
+func (it *LevelIterator[K, V, N]) Prev() {
+	it.SeekBefore(it.cur.GetKey())
+}
+
+func (it *LevelIterator[K, V, N]) SeekBefore(key K) {
+	it.cur = it.nodeOrNil(it.findLess(key))
+}
+
+func (it *LevelIterator[K, V, N]) Seek(key K) {
+	it.cur = it.findLess(key).NextAt(0)
+}
+
+func (it *LevelIterator[K, V, N]) SeekToFirst() {
+	it.cur = it.head.NextAt(0)
+}
+
+func (it *LevelIterator[K, V, N]) SeekToLast() {
+	it.cur = it.nodeOrNil(it.findLast())
+}
@@ -0,0 +1,64 @@
+package la
+
+import (
+	"cmp"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+)
+
+// IndexedLASkipList 額外維護 span 的 LASkipList，支援 Rank 與 Select。
+// 節點高度規則與 LASkipList 相同，PutWithNP 等方法皆可直接使用。
+type IndexedLASkipList[K cmp.Ordered, V any] struct {
+	*LASkipList[K, V]
+}
+
+func NewIndexedLASkipList(seed int64) *IndexedLASkipList[skiplist.K, skiplist.V] {
+	return NewIndexedLASkipListOf[skiplist.K, skiplist.V](seed)
+}
+
+// NewIndexedLASkipListOf 建立任意可排序 key 的 IndexedLASkipList
+func NewIndexedLASkipListOf[K cmp.Ordered, V any](seed int64) *IndexedLASkipList[K, V] {
+	sl := NewLASkipListOf[K, V](seed)
+	sl.indexed = true
+	sl.head.span = make([]int32, maxLevel+1)
+	for h := range sl.head.span {
+		sl.head.span[h] = 1
+	}
+	return &IndexedLASkipList[K, V]{sl}
+}
+
+// Rank 回傳小於 key 的元素個數，即 key 以 0 起算的排名；key 存在時第二個回傳值為 true
+func (sl *IndexedLASkipList[K, V]) Rank(key K) (int, bool) {
+	rank := int32(0)
+	cur := sl.head
+	for h := sl.level; h >= 0; h-- {
+		for cur.next[h] != nil && cur.next[h].key < key {
+			rank += cur.span[h]
+			cur = cur.next[h]
+		}
+	}
+	found := cur.next[0] != nil && cur.next[0].key == key
+	return int(rank), found
+}
+
+// Select 回傳第 i 小（0 起算）的元素，i 超出範圍時第三個回傳值為 false
+func (sl *IndexedLASkipList[K, V]) Select(i int) (K, V, bool) {
+	if i < 0 || i >= int(sl.size) {
+		var k K
+		var v V
+		return k, v, false
+	}
+	target := int32(i) + 1 // head 的排名為 0
+	traversed := int32(0)
+	cur := sl.head
+	for h := sl.level; h >= 0; h-- {
+		for cur.next[h] != nil && traversed+cur.span[h] <= target {
+			traversed += cur.span[h]
+			cur = cur.next[h]
+		}
+		if traversed == target {
+			break
+		}
+	}
+	return cur.key, cur.value, true
+}
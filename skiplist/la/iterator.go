@@ -0,0 +1,56 @@
+package la
+
+import (
+	"cmp"
+	"iter"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+)
+
+func (sl *LASkipList[K, V]) Iterator() skiplist.IteratorOf[K, V] {
+	return skiplist.NewLevelIterator[K, V](sl.head, &sl.level, cmp.Compare[K])
+}
+
+func (sl *LASkipList[K, V]) Range(lo, hi K, fn func(key K, value V) bool) {
+	skiplist.RangeOf(sl.Iterator(), cmp.Compare[K], lo, hi, fn)
+}
+
+func (sl *LASkipList[K, V]) All() iter.Seq2[K, V] {
+	return skiplist.AllOf(sl.Iterator)
+}
+
+func (sl *LASkipList[K, V]) Backward() iter.Seq2[K, V] {
+	return skiplist.BackwardOf(sl.Iterator)
+}
+
+func (sl *LASkipList[K, V]) Floor(key K) (K, V, bool) {
+	return skiplist.FloorOf(sl.Iterator(), cmp.Compare[K], key)
+}
+
+func (sl *LASkipList[K, V]) Ceiling(key K) (K, V, bool) {
+	return skiplist.CeilingOf(sl.Iterator(), key)
+}
+
+func (sl *LASkipList[K, V]) Predecessor(key K) (K, V, bool) {
+	return skiplist.PredecessorOf(sl.Iterator(), key)
+}
+
+func (sl *LASkipList[K, V]) Successor(key K) (K, V, bool) {
+	return skiplist.SuccessorOf(sl.Iterator(), cmp.Compare[K], key)
+}
+
+func (sl *LASkipList[K, V]) Min() (K, V, bool) {
+	return skiplist.MinOf(sl.Iterator())
+}
+
+func (sl *LASkipList[K, V]) Max() (K, V, bool) {
+	return skiplist.MaxOf(sl.Iterator())
+}
+
+func (sl *LASkipList[K, V]) PopMin() (K, V, bool) {
+	return skiplist.PopOf(sl.Min, sl.Delete)
+}
+
+func (sl *LASkipList[K, V]) PopMax() (K, V, bool) {
+	return skiplist.PopOf(sl.Max, sl.Delete)
+}
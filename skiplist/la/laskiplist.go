@@ -1,6 +1,7 @@
 package la
 
 import (
+	"cmp"
 	"math"
 	"math/rand"
 
@@ -12,31 +13,43 @@ const (
 	probability = 0.5
 )
 
-type laNode struct {
-	key   skiplist.K
-	value skiplist.V
-	next  []*laNode
+type laNode[K cmp.Ordered, V any] struct {
+	key   K
+	value V
+	next  []*laNode[K, V]
+	span  []int32 // 僅 IndexedLASkipList 使用，span[h] 為走到 next[h] 跨過的節點數
 }
 
-type LASkipList struct {
-	head  *laNode
-	level int32
-	rand  *rand.Rand
-	size  int32 // 數據集總元素數量（頻率基準）
+type LASkipList[K cmp.Ordered, V any] struct {
+	head    *laNode[K, V]
+	level   int32
+	rand    *rand.Rand
+	size    int32 // 數據集總元素數量（頻率基準）
+	indexed bool  // 是否維護 span
 }
 
-func newNode(key skiplist.K, value skiplist.V, level int32) *laNode {
-	n := &laNode{
+func newNode[K cmp.Ordered, V any](key K, value V, level int32, indexed bool) *laNode[K, V] {
+	n := &laNode[K, V]{
 		key:   key,
 		value: value,
-		next:  make([]*laNode, level+1),
+		next:  make([]*laNode[K, V], level+1),
+	}
+	if indexed {
+		n.span = make([]int32, level+1)
 	}
 	return n
 }
 
-func NewLASkipList(seed int64) *LASkipList {
-	return &LASkipList{
-		head:  newNode(0, 0, maxLevel),
+func NewLASkipList(seed int64) *LASkipList[skiplist.K, skiplist.V] {
+	return NewLASkipListOf[skiplist.K, skiplist.V](seed)
+}
+
+// NewLASkipListOf 建立任意可排序 key 的 LASkipList
+func NewLASkipListOf[K cmp.Ordered, V any](seed int64) *LASkipList[K, V] {
+	var headKey K
+	var zero V
+	return &LASkipList[K, V]{
+		head:  newNode(headKey, zero, maxLevel, false),
 		level: 1, // 初始化為 1 層
 		rand:  rand.New(rand.NewSource(seed)),
 	}
@@ -45,7 +58,7 @@ func NewLASkipList(seed int64) *LASkipList {
 // randomLevelWithNP 基於預測頻率計算節點高度
 // np: 預測的未來出現頻率 (n * prob)
 // l: 當前高度
-func (sl *LASkipList) randomLevelWithNP(np float64) int32 {
+func (sl *LASkipList[K, V]) randomLevelWithNP(np float64) int32 {
 	lvl := 0
 
 	for lvl < maxLevel {
@@ -67,7 +80,7 @@ func (sl *LASkipList) randomLevelWithNP(np float64) int32 {
 	return int32(lvl)
 }
 
-func (sl *LASkipList) find(key skiplist.K) (*laNode, bool) {
+func (sl *LASkipList[K, V]) find(key K) (*laNode[K, V], bool) {
 	cur := sl.head
 	for h := sl.level; h >= 0; h-- {
 		for cur.next[h] != nil && cur.next[h].key < key {
@@ -81,62 +94,80 @@ func (sl *LASkipList) find(key skiplist.K) (*laNode, bool) {
 }
 
 // PutWithNP 插入或更新 key 對應的 value，包含預測頻率
-func (sl *LASkipList) PutWithNP(key skiplist.K, value skiplist.V, np float64) {
+func (sl *LASkipList[K, V]) PutWithNP(key K, value V, np float64) (V, bool) {
 	if node, found := sl.find(key); found {
+		old := node.value
 		node.value = value
-		return
-	}
-
-	lvl := sl.randomLevelWithNP(np)
-	newNode := newNode(key, value, lvl)
-	sl.level = max(sl.level, lvl)
-
-	curr := sl.head
-	for h := sl.level; h >= 0; h-- {
-		for curr.next[h] != nil && curr.next[h].key < key {
-			curr = curr.next[h]
-		}
-		if h <= lvl {
-			newNode.next[h] = curr.next[h]
-			curr.next[h] = newNode
-		}
+		return old, true
 	}
-	sl.size++
 
+	sl.insert(key, value, sl.randomLevelWithNP(np))
+	var zero V
+	return zero, false
 }
 
 // Put 實現 SkipList 介面的 Put 方法，使用傳統隨機高度
-func (sl *LASkipList) Put(key skiplist.K, value skiplist.V) {
-	sl.PutWithoutProb(key, value)
+func (sl *LASkipList[K, V]) Put(key K, value V) (V, bool) {
+	return sl.PutWithoutProb(key, value)
 }
 
 // PutWithoutProb 不帶概率的 Put 方法，使用傳統的隨機高度
-func (sl *LASkipList) PutWithoutProb(key skiplist.K, value skiplist.V) {
+func (sl *LASkipList[K, V]) PutWithoutProb(key K, value V) (V, bool) {
 	if node, found := sl.find(key); found {
+		old := node.value
 		node.value = value
-		return
+		return old, true
 	}
 
-	lvl := sl.randomLevel()
-	newNode := newNode(key, value, lvl)
+	sl.insert(key, value, sl.randomLevel())
+	var zero V
+	return zero, false
+}
+
+// insert 以高度 lvl 插入不存在的 key，indexed 時一併維護 span
+func (sl *LASkipList[K, V]) insert(key K, value V, lvl int32) {
+	newNode := newNode(key, value, lvl, sl.indexed)
+	if sl.indexed {
+		for h := sl.level + 1; h <= lvl; h++ {
+			sl.head.span[h] = sl.size + 1
+		}
+	}
 	sl.level = max(sl.level, lvl)
 
+	var preds [maxLevel + 1]*laNode[K, V]
+	var rank [maxLevel + 1]int32 // rank[h]：第 h 層前驅節點的排名，head 為 0
 	curr := sl.head
 	for h := sl.level; h >= 0; h-- {
+		if h < sl.level {
+			rank[h] = rank[h+1]
+		}
 		for curr.next[h] != nil && curr.next[h].key < key {
+			if sl.indexed {
+				rank[h] += curr.span[h]
+			}
 			curr = curr.next[h]
 		}
-		if h <= lvl {
-			newNode.next[h] = curr.next[h]
-			curr.next[h] = newNode
+		preds[h] = curr
+	}
+	for h := int32(0); h <= lvl; h++ {
+		newNode.next[h] = preds[h].next[h]
+		preds[h].next[h] = newNode
+	}
+	if sl.indexed {
+		for h := int32(0); h <= sl.level; h++ {
+			if h <= lvl {
+				newNode.span[h] = preds[h].span[h] - (rank[0] - rank[h])
+				preds[h].span[h] = rank[0] - rank[h] + 1
+			} else {
+				preds[h].span[h]++
+			}
 		}
 	}
-
 	sl.size++
 }
 
 // 傳統的隨機高度計算方法
-func (sl *LASkipList) randomLevel() int32 {
+func (sl *LASkipList[K, V]) randomLevel() int32 {
 	lvl := 0
 	for sl.rand.Float64() < probability && lvl < maxLevel {
 		lvl++
@@ -145,31 +176,46 @@ func (sl *LASkipList) randomLevel() int32 {
 }
 
 // Get 取得 key 對應的 value
-func (sl *LASkipList) Get(key skiplist.K) (skiplist.V, bool) {
+func (sl *LASkipList[K, V]) Get(key K) (V, bool) {
 	node, found := sl.find(key)
 	if found {
 		return node.value, true
 	}
-	return 0, false
+	var zero V
+	return zero, false
 }
 
 // Contains 判斷 key 是否存在
-func (sl *LASkipList) Contains(key skiplist.K) bool {
+func (sl *LASkipList[K, V]) Contains(key K) bool {
 	_, found := sl.find(key)
 	return found
 }
 
 // Delete 刪除 key
-func (sl *LASkipList) Delete(key skiplist.K) {
+func (sl *LASkipList[K, V]) Delete(key K) (V, bool) {
 	curh := sl.level
 	curr := sl.head
 
+	var preds [maxLevel + 1]*laNode[K, V]
 	for h := curh; h >= 0; h-- {
 		for curr.next[h] != nil && curr.next[h].key < key {
 			curr = curr.next[h]
 		}
-		if curr.next[h] != nil && curr.next[h].key == key {
-			curr.next[h] = curr.next[h].next[h]
+		preds[h] = curr
+	}
+	target := curr.next[0]
+	if target == nil || target.key != key {
+		var zero V
+		return zero, false
+	}
+	for h := curh; h >= 0; h-- {
+		if preds[h].next[h] == target {
+			if sl.indexed {
+				preds[h].span[h] += target.span[h] - 1
+			}
+			preds[h].next[h] = target.next[h]
+		} else if sl.indexed {
+			preds[h].span[h]--
 		}
 	}
 
@@ -179,6 +225,7 @@ func (sl *LASkipList) Delete(key skiplist.K) {
 	}
 	sl.level = newlvl
 	sl.size--
+	return target.value, true
 }
 
 // 輔助函數
@@ -189,28 +236,37 @@ func max(a, b int32) int32 {
 	return b
 }
 
-func (sl *LASkipList) GetMaxStats() (int, int) {
+// Len 回傳目前的元素個數
+func (sl *LASkipList[K, V]) Len() int {
+	return int(sl.size)
+}
+
+func (sl *LASkipList[K, V]) GetMaxStats() (int, int) {
 	return int(sl.size), int(sl.level)
 }
 
-func (sl *LASkipList) GetHead() skiplist.Nodelike {
+func (sl *LASkipList[K, V]) Compare(a, b K) int {
+	return cmp.Compare(a, b)
+}
+
+func (sl *LASkipList[K, V]) GetHead() skiplist.NodelikeOf[K, V] {
 	return sl.head
 }
 
 // Node 實作 Nodelike 介面
-func (n *laNode) GetKey() skiplist.K {
+func (n *laNode[K, V]) GetKey() K {
 	return n.key
 }
 
-func (n *laNode) GetValue() skiplist.V {
+func (n *laNode[K, V]) GetValue() V {
 	return n.value
 }
 
-func (n *laNode) GetLevel() int32 {
+func (n *laNode[K, V]) GetLevel() int32 {
 	return int32(len(n.next) - 1)
 }
 
-func (n *laNode) GetNextAt(level int32) skiplist.Nodelike {
+func (n *laNode[K, V]) GetNextAt(level int32) skiplist.NodelikeOf[K, V] {
 	if level < 0 || level >= int32(len(n.next)) {
 		return nil
 	}
@@ -219,3 +275,8 @@ func (n *laNode) GetNextAt(level int32) skiplist.Nodelike {
 	}
 	return n.next[level]
 }
+
+// NextAt 回傳第 h 層的下一個節點，供 skiplist.LevelIterator 走訪
+func (n *laNode[K, V]) NextAt(h int32) *laNode[K, V] {
+	return n.next[h]
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/Hakuto4838/SkipList.git/datastream"
 	"github.com/Hakuto4838/SkipList.git/skiplist"
 	"github.com/Hakuto4838/SkipList.git/skiplist/analyTool"
+	"github.com/Hakuto4838/SkipList.git/skiplist/skiplisttest"
+	"github.com/Hakuto4838/SkipList.git/skiplist/syncsl"
 )
 
 func TestLASkipList(t *testing.T) {
@@ -23,7 +25,7 @@ func TestLASkipList(t *testing.T) {
 
 	// 測試 Get
 	if value, found := sl.Get(1); !found || value != 100 {
-		t.Errorf("Get(1) = (%d, %v), want (100, true)", value, found)
+		t.Errorf("Get(1) = (%f, %v), want (100, true)", value, found)
 	}
 
 	// 測試 Contains
@@ -38,9 +40,29 @@ func TestLASkipList(t *testing.T) {
 	}
 }
 
+func TestLASkipListGeneric(t *testing.T) {
+	sl := NewLASkipListOf[string, int](42)
+
+	sl.PutWithNP("hot", 1, 64)
+	sl.Put("cold", 2)
+	sl.Put("warm", 3)
+
+	if value, found := sl.Get("hot"); !found || value != 1 {
+		t.Errorf("Get(hot) = (%d, %v), want (1, true)", value, found)
+	}
+	if node, _ := sl.find("hot"); node.GetLevel() < 7 {
+		t.Errorf("hot level = %d, want >= 7 for np=64", node.GetLevel())
+	}
+
+	sl.Delete("cold")
+	if sl.Contains("cold") {
+		t.Error("Contains(cold) = true after delete, want false")
+	}
+}
+
 // 複雜的併發測試 - 混合讀寫操作
 func TestComplexConcurrentOperations(t *testing.T) {
-	sl := NewLASkipList(42)
+	sl := syncsl.NewSyncSkipList(NewLASkipList(42), syncsl.SharedRead)
 	const numGoroutines = 50
 	const operationsPerGoroutine = 100
 	const keyRange = 1000
@@ -88,7 +110,7 @@ func TestComplexConcurrentOperations(t *testing.T) {
 
 // 壓力測試 - 大量併發寫入
 func TestStressTestConcurrentWrites(t *testing.T) {
-	sl := NewLASkipList(42)
+	sl := syncsl.NewSyncSkipList(NewLASkipList(42), syncsl.SharedRead)
 	const numWriters = 20
 	const writesPerWriter = 500
 	const keyRange = 200
@@ -127,7 +149,7 @@ func TestStressTestConcurrentWrites(t *testing.T) {
 
 // 競爭條件測試 - 同時讀寫相同鍵
 func TestRaceConditionSameKey(t *testing.T) {
-	sl := NewLASkipList(42)
+	sl := syncsl.NewSyncSkipList(NewLASkipList(42), syncsl.SharedRead)
 	const numGoroutines = 30
 	const operationsPerGoroutine = 50
 	const testKey = skiplist.K(42)
@@ -162,7 +184,7 @@ func TestRaceConditionSameKey(t *testing.T) {
 	// 驗證最終狀態
 	if value, found := sl.Get(testKey); found {
 		finalValue = value
-		t.Logf("競爭條件測試完成: 最終值為 %d", finalValue)
+		t.Logf("競爭條件測試完成: 最終值為 %f", finalValue)
 	} else {
 		t.Log("競爭條件測試完成: 鍵被刪除")
 	}
@@ -174,7 +196,7 @@ func TestLongRunningConcurrentTest(t *testing.T) {
 		t.Skip("跳過長時間測試")
 	}
 
-	sl := NewLASkipList(42)
+	sl := syncsl.NewSyncSkipList(NewLASkipList(42), syncsl.SharedRead)
 	const testDuration = 5 * time.Second
 	const numGoroutines = 10
 
@@ -221,7 +243,7 @@ func TestLongRunningConcurrentTest(t *testing.T) {
 
 // 邊界條件測試
 func TestEdgeCasesConcurrent(t *testing.T) {
-	sl := NewLASkipList(42)
+	sl := syncsl.NewSyncSkipList(NewLASkipList(42), syncsl.SharedRead)
 	const numGoroutines = 20
 
 	var wg sync.WaitGroup
@@ -266,7 +288,7 @@ func TestEdgeCasesConcurrent(t *testing.T) {
 
 // 性能基準測試
 func BenchmarkConcurrentOperations(b *testing.B) {
-	sl := NewLASkipList(42)
+	sl := syncsl.NewSyncSkipList(NewLASkipList(42), syncsl.SharedRead)
 	const numGoroutines = 10
 
 	b.ResetTimer()
@@ -292,7 +314,7 @@ func BenchmarkConcurrentOperations(b *testing.B) {
 
 // 驗證資料一致性測試 - 修正版本
 func TestDataConsistencyConcurrent(t *testing.T) {
-	sl := NewLASkipList(42)
+	sl := syncsl.NewSyncSkipList(NewLASkipList(42), syncsl.SharedRead)
 	const numOperations = 1000
 	const numReaders = 10
 
@@ -346,7 +368,7 @@ func TestDataConsistencyConcurrent(t *testing.T) {
 
 // 測試併發刪除操作
 func TestConcurrentDeletes(t *testing.T) {
-	sl := NewLASkipList(42)
+	sl := syncsl.NewSyncSkipList(NewLASkipList(42), syncsl.SharedRead)
 	const numKeys = 100
 	const numDeleters = 5
 
@@ -419,3 +441,59 @@ func TestBasicSkipList_Analyable(t *testing.T) {
 		t.Errorf("pstep length: %d, kmap length: %d", len(pstep), len(kmap))
 	}
 }
+
+func TestLASkipListConformance(t *testing.T) {
+	skiplisttest.Run(t, func() skiplist.SkipList { return NewLASkipList(42) })
+}
+
+func TestIndexedLASkipListRankSelect(t *testing.T) {
+	var _ skiplist.Indexable = NewIndexedLASkipList(42)
+	sl := NewIndexedLASkipList(42)
+	r := rand.New(rand.NewSource(7))
+	live := map[skiplist.K]bool{}
+	for op := 0; op < 3000; op++ {
+		key := skiplist.K(r.Intn(500))
+		if r.Intn(3) == 0 {
+			sl.Delete(key)
+			delete(live, key)
+		} else {
+			sl.PutWithNP(key, skiplist.V(key), float64(r.Intn(64)))
+			live[key] = true
+		}
+	}
+	check := func() {
+		rank := 0
+		for key := skiplist.K(-1); key <= 500; key++ {
+			got, found := sl.Rank(key)
+			if got != rank || found != live[key] {
+				t.Fatalf("Rank(%d) = (%d, %v), want (%d, %v)", key, got, found, rank, live[key])
+			}
+			if live[key] {
+				k, v, ok := sl.Select(rank)
+				if !ok || k != key || v != skiplist.V(key) {
+					t.Fatalf("Select(%d) = (%d, %f, %v), want (%d, %d, true)", rank, k, v, ok, key, key)
+				}
+				rank++
+			}
+		}
+		if _, _, ok := sl.Select(rank); ok {
+			t.Errorf("Select(%d) ok with %d elements, want false", rank, rank)
+		}
+		if _, _, ok := sl.Select(-1); ok {
+			t.Error("Select(-1) ok, want false")
+		}
+	}
+	check()
+
+	// 全部刪除再插入，確認層級縮減後 span 仍正確
+	for key := range live {
+		sl.Delete(key)
+	}
+	clear(live)
+	for i := 0; i < 50; i++ {
+		key := skiplist.K(r.Intn(500))
+		sl.PutWithNP(key, skiplist.V(key), float64(r.Intn(64)))
+		live[key] = true
+	}
+	check()
+}
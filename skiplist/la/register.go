@@ -0,0 +1,17 @@
+package la
+
+import (
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/registry"
+)
+
+func init() {
+	registry.Register(registry.Impl{
+		Name:       "la",
+		Usage:      "依預估存取頻率決定高度的 skip list，插入時使用分布中的機率",
+		SharedRead: true,
+		New: func(p registry.Params, env registry.Env) (skiplist.SkipList, error) {
+			return NewLASkipList(env.Seed), nil
+		},
+	})
+}
@@ -0,0 +1,147 @@
+package lockfree
+
+import (
+	"cmp"
+	"iter"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+)
+
+// lfIterator 是弱一致的迭代器：走訪期間其他 goroutine 的修改可能看得到也可能看不到，
+// 但結果一定依序且不重複
+type lfIterator struct {
+	sl  *LockFreeList
+	cur *lfNode
+}
+
+// findLast 回傳最後一個未刪除的節點，串列為空時回傳 head
+func (sl *LockFreeList) findLast() *lfNode {
+	pred := sl.head
+	for h := sl.level.Load(); h >= 0; h-- {
+		curr := pred.next[h].Load().node
+		for curr != nil {
+			ref := curr.next[h].Load()
+			if !ref.marked {
+				pred = curr
+			}
+			curr = ref.node
+		}
+	}
+	return pred
+}
+
+// nextAlive 回傳 nd 之後第一個未刪除的節點
+func nextAlive(nd *lfNode) *lfNode {
+	next := nd.next[0].Load().node
+	for next != nil {
+		ref := next.next[0].Load()
+		if !ref.marked {
+			return next
+		}
+		next = ref.node
+	}
+	return nil
+}
+
+// nodeOrNil 將 head 轉換為 nil，表示迭代器已越界
+func (sl *LockFreeList) nodeOrNil(nd *lfNode) *lfNode {
+	if nd == sl.head {
+		return nil
+	}
+	return nd
+}
+
+func (sl *LockFreeList) Iterator() skiplist.IteratorOf[skiplist.K, skiplist.V] {
+	return &lfIterator{sl: sl}
+}
+
+func (sl *LockFreeList) Range(lo, hi skiplist.K, fn func(key skiplist.K, value skiplist.V) bool) {
+	skiplist.RangeOf(sl.Iterator(), cmp.Compare[skiplist.K], lo, hi, fn)
+}
+
+func (sl *LockFreeList) All() iter.Seq2[skiplist.K, skiplist.V] {
+	return skiplist.AllOf(sl.Iterator)
+}
+
+func (sl *LockFreeList) Backward() iter.Seq2[skiplist.K, skiplist.V] {
+	return skiplist.BackwardOf(sl.Iterator)
+}
+
+func (sl *LockFreeList) Floor(key skiplist.K) (skiplist.K, skiplist.V, bool) {
+	return skiplist.FloorOf(sl.Iterator(), cmp.Compare[skiplist.K], key)
+}
+
+func (sl *LockFreeList) Ceiling(key skiplist.K) (skiplist.K, skiplist.V, bool) {
+	return skiplist.CeilingOf(sl.Iterator(), key)
+}
+
+func (sl *LockFreeList) Predecessor(key skiplist.K) (skiplist.K, skiplist.V, bool) {
+	return skiplist.PredecessorOf(sl.Iterator(), key)
+}
+
+func (sl *LockFreeList) Successor(key skiplist.K) (skiplist.K, skiplist.V, bool) {
+	return skiplist.SuccessorOf(sl.Iterator(), cmp.Compare[skiplist.K], key)
+}
+
+func (sl *LockFreeList) Min() (skiplist.K, skiplist.V, bool) {
+	return skiplist.MinOf(sl.Iterator())
+}
+
+func (sl *LockFreeList) Max() (skiplist.K, skiplist.V, bool) {
+	return skiplist.MaxOf(sl.Iterator())
+}
+
+// PopMin 只回傳自己成功刪除的元素，多個消費者不會取得同一個元素
+func (sl *LockFreeList) PopMin() (skiplist.K, skiplist.V, bool) {
+	return sl.pop(sl.Min)
+}
+
+// PopMax 只回傳自己成功刪除的元素
+func (sl *LockFreeList) PopMax() (skiplist.K, skiplist.V, bool) {
+	return sl.pop(sl.Max)
+}
+
+// pop 反覆以 peek 取得候選元素並嘗試刪除，直到刪除成功或串列為空
+func (sl *LockFreeList) pop(peek func() (skiplist.K, skiplist.V, bool)) (skiplist.K, skiplist.V, bool) {
+	for {
+		k, _, ok := peek()
+		if !ok {
+			return 0, 0, false
+		}
+		if v, found := sl.Delete(k); found {
+			return k, v, true
+		}
+	}
+}
+
+func (it *lfIterator) Valid() bool     { return it.cur != nil }
+func (it *lfIterator) Key() skiplist.K { return it.cur.key }
+
+func (it *lfIterator) Value() skiplist.V {
+	return it.cur.next[0].Load().value
+}
+
+func (it *lfIterator) Next() {
+	it.cur = nextAlive(it.cur)
+}
+
+func (it *lfIterator) Prev() {
+	it.SeekBefore(it.cur.key)
+}
+
+func (it *lfIterator) SeekBefore(key skiplist.K) {
+	pred, _ := it.sl.scan(key)
+	it.cur = it.sl.nodeOrNil(pred)
+}
+
+func (it *lfIterator) Seek(key skiplist.K) {
+	_, it.cur = it.sl.scan(key)
+}
+
+func (it *lfIterator) SeekToFirst() {
+	it.cur = nextAlive(it.sl.head)
+}
+
+func (it *lfIterator) SeekToLast() {
+	it.cur = it.sl.nodeOrNil(it.sl.findLast())
+}
@@ -0,0 +1,288 @@
+package lockfree
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"sync/atomic"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+)
+
+const (
+	maxLevel    = 32
+	probability = 0.5
+)
+
+// markRef 是不可變的 (後繼, 刪除標記) 組合，以 CAS 整個替換來模擬帶標記的指標。
+// 第 0 層的 markRef 另外帶著節點的 value，讓更新值與刪除標記落在同一個 CAS 上，
+// 避免刪除回傳的值與同時進行的 Put 不一致。
+type markRef struct {
+	node   *lfNode
+	marked bool       // 擁有此 next 的節點已在這一層被邏輯刪除
+	value  skiplist.V // 只在第 0 層有意義
+}
+
+type lfNode struct {
+	key  skiplist.K
+	next []atomic.Pointer[markRef]
+}
+
+// LockFreeList 以 CAS 串接各層的無鎖 skip list（Fraser / Herlihy-Shavit）。
+// 刪除先由上而下標記節點每一層的 next，再由之後經過的搜尋協助實體移除；
+// Get 與 Contains 只讀取不寫入，不會被其他操作阻擋。
+type LockFreeList struct {
+	head  *lfNode
+	level atomic.Int32 // 目前用到的最高層，只增不減
+	size  atomic.Int64
+}
+
+func newNode(key skiplist.K, level int32) *lfNode {
+	return &lfNode{
+		key:  key,
+		next: make([]atomic.Pointer[markRef], level+1),
+	}
+}
+
+func NewLockFreeList() *LockFreeList {
+	head := newNode(-1, maxLevel)
+	for h := range head.next {
+		head.next[h].Store(&markRef{})
+	}
+	sl := &LockFreeList{head: head}
+	sl.level.Store(1)
+	return sl
+}
+
+func randomLevel() int32 {
+	lvl := int32(0)
+	for rand.Float64() < probability && lvl < maxLevel {
+		lvl++
+	}
+	return lvl
+}
+
+// find 搜尋 key，並把每層最後一個小於 key 的節點與其後繼記錄在 preds 與 succs。
+// 途經已標記的節點會以 CAS 協助移除，CAS 失敗表示前驅已變動，需從頭重試。
+func (sl *LockFreeList) find(key skiplist.K, preds, succs []*lfNode) bool {
+retry:
+	for {
+		pred := sl.head
+		var curr *lfNode
+		for h := sl.level.Load(); h >= 0; h-- {
+			curr = pred.next[h].Load().node
+			for curr != nil {
+				ref := curr.next[h].Load()
+				if ref.marked {
+					old := pred.next[h].Load()
+					if old.node != curr || old.marked {
+						continue retry
+					}
+					if !pred.next[h].CompareAndSwap(old, &markRef{node: ref.node, value: old.value}) {
+						continue retry
+					}
+					curr = ref.node
+					continue
+				}
+				if curr.key >= key {
+					break
+				}
+				pred = curr
+				curr = ref.node
+			}
+			preds[h] = pred
+			succs[h] = curr
+		}
+		return curr != nil && curr.key == key
+	}
+}
+
+// scan 不協助移除的唯讀搜尋，回傳第 0 層最後一個小於 key 的節點與第一個 >= key 的未刪除節點
+func (sl *LockFreeList) scan(key skiplist.K) (pred, curr *lfNode) {
+	pred = sl.head
+	for h := sl.level.Load(); h >= 0; h-- {
+		curr = pred.next[h].Load().node
+		for curr != nil {
+			ref := curr.next[h].Load()
+			if ref.marked {
+				curr = ref.node
+				continue
+			}
+			if curr.key >= key {
+				break
+			}
+			pred = curr
+			curr = ref.node
+		}
+	}
+	return pred, curr
+}
+
+// raiseLevel 將 sl.level 提高到至少 lvl
+func (sl *LockFreeList) raiseLevel(lvl int32) {
+	for {
+		cur := sl.level.Load()
+		if lvl <= cur || sl.level.CompareAndSwap(cur, lvl) {
+			return
+		}
+	}
+}
+
+func (sl *LockFreeList) Put(key skiplist.K, value skiplist.V) (skiplist.V, bool) {
+	var preds, succs [maxLevel + 1]*lfNode
+	lvl := randomLevel()
+	sl.raiseLevel(lvl)
+	for {
+		if sl.find(key, preds[:], succs[:]) {
+			if old, ok := sl.replace(succs[0], value); ok {
+				return old, true
+			}
+			// 節點正在被刪除，等它被移除後改為插入
+			continue
+		}
+
+		nd := newNode(key, lvl)
+		nd.next[0].Store(&markRef{node: succs[0], value: value})
+		for h := int32(1); h <= lvl; h++ {
+			nd.next[h].Store(&markRef{node: succs[h]})
+		}
+		// 第 0 層連上即代表插入完成
+		old := preds[0].next[0].Load()
+		if old.node != succs[0] || old.marked || !preds[0].next[0].CompareAndSwap(old, &markRef{node: nd, value: old.value}) {
+			continue
+		}
+		sl.size.Add(1)
+		sl.linkUpper(nd, lvl, preds[:], succs[:])
+		var zero skiplist.V
+		return zero, false
+	}
+}
+
+// replace 以 CAS 更新節點的值，節點已被刪除時回傳 false
+func (sl *LockFreeList) replace(nd *lfNode, value skiplist.V) (skiplist.V, bool) {
+	for {
+		ref := nd.next[0].Load()
+		if ref.marked {
+			return 0, false
+		}
+		if nd.next[0].CompareAndSwap(ref, &markRef{node: ref.node, value: value}) {
+			return ref.value, true
+		}
+	}
+}
+
+// linkUpper 將已在第 0 層的節點逐層接上，節點被刪除時停止
+func (sl *LockFreeList) linkUpper(nd *lfNode, lvl int32, preds, succs []*lfNode) {
+	for h := int32(1); h <= lvl; h++ {
+		for {
+			ref := nd.next[h].Load()
+			if ref.marked {
+				return
+			}
+			if ref.node != succs[h] && !nd.next[h].CompareAndSwap(ref, &markRef{node: succs[h]}) {
+				continue
+			}
+			old := preds[h].next[h].Load()
+			if old.node == succs[h] && !old.marked && preds[h].next[h].CompareAndSwap(old, &markRef{node: nd}) {
+				break
+			}
+			sl.find(nd.key, preds, succs)
+			if succs[0] != nd {
+				// 節點已被刪除並移除
+				return
+			}
+		}
+	}
+}
+
+func (sl *LockFreeList) Delete(key skiplist.K) (skiplist.V, bool) {
+	var preds, succs [maxLevel + 1]*lfNode
+	if !sl.find(key, preds[:], succs[:]) {
+		return 0, false
+	}
+	victim := succs[0]
+	for h := int32(len(victim.next) - 1); h >= 1; h-- {
+		for {
+			ref := victim.next[h].Load()
+			if ref.marked || victim.next[h].CompareAndSwap(ref, &markRef{node: ref.node, marked: true}) {
+				break
+			}
+		}
+	}
+	for {
+		ref := victim.next[0].Load()
+		if ref.marked {
+			// 其他 goroutine 搶先刪除
+			return 0, false
+		}
+		if victim.next[0].CompareAndSwap(ref, &markRef{node: ref.node, marked: true, value: ref.value}) {
+			sl.size.Add(-1)
+			sl.find(key, preds[:], succs[:])
+			return ref.value, true
+		}
+	}
+}
+
+func (sl *LockFreeList) Get(key skiplist.K) (skiplist.V, bool) {
+	_, nd := sl.scan(key)
+	if nd == nil || nd.key != key {
+		return 0, false
+	}
+	ref := nd.next[0].Load()
+	if ref.marked {
+		return 0, false
+	}
+	return ref.value, true
+}
+
+func (sl *LockFreeList) Contains(key skiplist.K) bool {
+	_, nd := sl.scan(key)
+	return nd != nil && nd.key == key
+}
+
+func (sl *LockFreeList) Len() int {
+	return int(sl.size.Load())
+}
+
+func (sl *LockFreeList) GetHead() skiplist.Nodelike {
+	return sl.head
+}
+
+// GetMaxStats 只在沒有其他 goroutine 寫入時才精確
+func (sl *LockFreeList) GetMaxStats() (int, int) {
+	top := sl.level.Load()
+	for top > 1 && sl.head.next[top].Load().node == nil {
+		top--
+	}
+	return sl.Len(), int(top)
+}
+
+func (sl *LockFreeList) Compare(a, b skiplist.K) int {
+	return cmp.Compare(a, b)
+}
+
+func (nd *lfNode) GetKey() skiplist.K {
+	return nd.key
+}
+
+func (nd *lfNode) GetValue() skiplist.V {
+	return nd.next[0].Load().value
+}
+
+func (nd *lfNode) GetLevel() int32 {
+	return int32(len(nd.next) - 1)
+}
+
+// GetNextAt 略過已標記刪除的後繼
+func (nd *lfNode) GetNextAt(level int32) skiplist.Nodelike {
+	if level < 0 || level >= int32(len(nd.next)) {
+		return nil
+	}
+	next := nd.next[level].Load().node
+	for next != nil && next.next[level].Load().marked {
+		next = next.next[level].Load().node
+	}
+	if next == nil {
+		return nil
+	}
+	return next
+}
@@ -0,0 +1,299 @@
+package lockfree
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/analyTool"
+	"github.com/Hakuto4838/SkipList.git/skiplist/skiplisttest"
+)
+
+func TestLockFreeListInterface(t *testing.T) {
+	var _ skiplist.SkipList = (*LockFreeList)(nil)
+	var _ skiplist.Analyable = (*LockFreeList)(nil)
+	var _ skiplist.Nodelike = (*lfNode)(nil)
+}
+
+func TestLockFreeListConformance(t *testing.T) {
+	skiplisttest.Run(t, func() skiplist.SkipList { return NewLockFreeList() })
+}
+
+func TestLockFreeListBasic(t *testing.T) {
+	sl := NewLockFreeList()
+	for i := 0; i < 100; i++ {
+		if _, replaced := sl.Put(skiplist.K(i), skiplist.V(i*10)); replaced {
+			t.Fatalf("Put(%d) on new key replaced = true", i)
+		}
+	}
+	if old, replaced := sl.Put(5, 55); !replaced || old != 50 {
+		t.Errorf("Put(5) = (%f, %v), want (50, true)", old, replaced)
+	}
+	for i := 0; i < 100; i += 2 {
+		if _, found := sl.Delete(skiplist.K(i)); !found {
+			t.Errorf("Delete(%d) found = false", i)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		value, found := sl.Get(skiplist.K(i))
+		want := skiplist.V(i * 10)
+		if i == 5 {
+			want = 55
+		}
+		if found != (i%2 == 1) || (found && value != want) {
+			t.Errorf("Get(%d) = (%f, %v), want (%f, %v)", i, value, found, want, i%2 == 1)
+		}
+	}
+	if sl.Len() != 50 {
+		t.Errorf("Len() = %d, want 50", sl.Len())
+	}
+
+	// 靜止狀態下 analyTool 走訪的結構應與 Len 一致
+	size, _ := sl.GetMaxStats()
+	avg, _ := analyTool.AnalyzeStep(sl, map[skiplist.K]float64{1: 1, 51: 1})
+	if size != 50 || avg <= 0 {
+		t.Errorf("GetMaxStats() size = %d, AnalyzeStep avg = %f", size, avg)
+	}
+}
+
+func TestLockFreeListIterator(t *testing.T) {
+	sl := NewLockFreeList()
+	for i := 0; i < 20; i++ {
+		sl.Put(skiplist.K(i*2), skiplist.V(i))
+	}
+	sl.Delete(10)
+
+	prev := skiplist.K(-1)
+	n := 0
+	for k := range sl.All() {
+		if k <= prev || k == 10 {
+			t.Fatalf("All() yielded %d after %d", k, prev)
+		}
+		prev = k
+		n++
+	}
+	if n != 19 {
+		t.Errorf("All() yielded %d keys, want 19", n)
+	}
+
+	if k, _, ok := sl.Floor(11); !ok || k != 8 {
+		t.Errorf("Floor(11) = (%d, %v), want (8, true)", k, ok)
+	}
+	if k, _, ok := sl.Successor(8); !ok || k != 12 {
+		t.Errorf("Successor(8) = (%d, %v), want (12, true)", k, ok)
+	}
+	if k, _, ok := sl.PopMax(); !ok || k != 38 {
+		t.Errorf("PopMax() = (%d, %v), want (38, true)", k, ok)
+	}
+}
+
+// 以下併發測試沿用 la/laskiplist_test.go 的模式，需搭配 go test -race
+
+// 複雜的併發測試 - 混合讀寫操作
+func TestComplexConcurrentOperations(t *testing.T) {
+	sl := NewLockFreeList()
+	const numGoroutines = 50
+	const operationsPerGoroutine = 100
+	const keyRange = 1000
+
+	var wg sync.WaitGroup
+	var readCount, writeCount, deleteCount int64
+
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func(goroutineID int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(goroutineID)))
+
+			for j := 0; j < operationsPerGoroutine; j++ {
+				operation := r.Intn(4) // 0: Put, 1: Get, 2: Contains, 3: Delete
+				key := skiplist.K(r.Intn(keyRange))
+
+				switch operation {
+				case 0:
+					sl.Put(key, skiplist.V(r.Intn(10000)))
+					atomic.AddInt64(&writeCount, 1)
+				case 1:
+					sl.Get(key)
+					atomic.AddInt64(&readCount, 1)
+				case 2:
+					sl.Contains(key)
+					atomic.AddInt64(&readCount, 1)
+				case 3:
+					sl.Delete(key)
+					atomic.AddInt64(&deleteCount, 1)
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	n := 0
+	for range sl.All() {
+		n++
+	}
+	if n != sl.Len() {
+		t.Errorf("All() yielded %d keys, Len() = %d", n, sl.Len())
+	}
+	t.Logf("併發測試完成: 讀取操作 %d, 寫入操作 %d, 刪除操作 %d", readCount, writeCount, deleteCount)
+}
+
+// 壓力測試 - 大量併發寫入，每個 writer 負責互不重疊的 key，結束後內容必須完全正確
+func TestStressTestConcurrentWrites(t *testing.T) {
+	sl := NewLockFreeList()
+	const numWriters = 20
+	const writesPerWriter = 500
+
+	var wg sync.WaitGroup
+	for i := 0; i < numWriters; i++ {
+		wg.Add(1)
+		go func(writerID int) {
+			defer wg.Done()
+			for j := 0; j < writesPerWriter; j++ {
+				key := skiplist.K(j*numWriters + writerID)
+				sl.Put(key, skiplist.V(key))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if sl.Len() != numWriters*writesPerWriter {
+		t.Errorf("Len() = %d, want %d", sl.Len(), numWriters*writesPerWriter)
+	}
+	for i := 0; i < numWriters*writesPerWriter; i++ {
+		if value, found := sl.Get(skiplist.K(i)); !found || value != skiplist.V(i) {
+			t.Fatalf("Get(%d) = (%f, %v), want (%d, true)", i, value, found, i)
+		}
+	}
+}
+
+// 競爭條件測試 - 同時讀寫刪除相同鍵
+func TestRaceConditionSameKey(t *testing.T) {
+	sl := NewLockFreeList()
+	const numGoroutines = 30
+	const operationsPerGoroutine = 50
+	const testKey = skiplist.K(42)
+
+	var wg sync.WaitGroup
+	var inserted, deleted int64
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func(goroutineID int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(goroutineID)))
+			for j := 0; j < operationsPerGoroutine; j++ {
+				switch r.Intn(4) {
+				case 0:
+					if _, replaced := sl.Put(testKey, skiplist.V(goroutineID*100+j)); !replaced {
+						atomic.AddInt64(&inserted, 1)
+					}
+				case 1:
+					sl.Get(testKey)
+				case 2:
+					sl.Contains(testKey)
+				case 3:
+					if _, found := sl.Delete(testKey); found {
+						atomic.AddInt64(&deleted, 1)
+					}
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// 每次成功插入與成功刪除必須成對，最後最多剩下一個
+	live := inserted - deleted
+	if live != int64(sl.Len()) || (live == 1) != sl.Contains(testKey) {
+		t.Errorf("inserted %d, deleted %d, Len() = %d, Contains = %v", inserted, deleted, sl.Len(), sl.Contains(testKey))
+	}
+}
+
+// 測試併發刪除操作：每個 key 只能被刪除成功一次
+func TestConcurrentDeletes(t *testing.T) {
+	sl := NewLockFreeList()
+	const numKeys = 1000
+	const numDeleters = 8
+	for i := 0; i < numKeys; i++ {
+		sl.Put(skiplist.K(i), skiplist.V(i))
+	}
+
+	var wg sync.WaitGroup
+	var deleteCount int64
+	for i := 0; i < numDeleters; i++ {
+		wg.Add(1)
+		go func(deleterID int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(deleterID)))
+			for _, k := range r.Perm(numKeys) {
+				if v, found := sl.Delete(skiplist.K(k)); found {
+					if v != skiplist.V(k) {
+						t.Errorf("Delete(%d) = %f, want %d", k, v, k)
+					}
+					atomic.AddInt64(&deleteCount, 1)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if deleteCount != numKeys || sl.Len() != 0 {
+		t.Errorf("deleted %d keys, Len() = %d, want %d and 0", deleteCount, sl.Len(), numKeys)
+	}
+	if _, _, ok := sl.Min(); ok {
+		t.Error("Min() on emptied list ok = true")
+	}
+}
+
+func TestLockFreeListConcurrentPopMin(t *testing.T) {
+	sl := NewLockFreeList()
+	const n = 2000
+	for i := 0; i < n; i++ {
+		sl.Put(skiplist.K(i), skiplist.V(i))
+	}
+
+	popped := make([]int32, n)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				k, _, ok := sl.PopMin()
+				if !ok {
+					return
+				}
+				atomic.AddInt32(&popped[k], 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for k, c := range popped {
+		if c != 1 {
+			t.Fatalf("key %d popped %d times, want once", k, c)
+		}
+	}
+}
+
+func BenchmarkConcurrentOperations(b *testing.B) {
+	sl := NewLockFreeList()
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(rand.Int63()))
+		for pb.Next() {
+			key := skiplist.K(r.Intn(1000))
+			switch r.Intn(4) {
+			case 0:
+				sl.Put(key, skiplist.V(key))
+			case 1:
+				sl.Get(key)
+			case 2:
+				sl.Contains(key)
+			case 3:
+				sl.Delete(key)
+			}
+		}
+	})
+}
@@ -0,0 +1,18 @@
+package lockfree
+
+import (
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/registry"
+)
+
+func init() {
+	registry.Register(registry.Impl{
+		Name:       "lockfree",
+		Usage:      "以 CAS 實作的 lock-free skip list",
+		ThreadSafe: true,
+		SharedRead: true,
+		New: func(p registry.Params, env registry.Env) (skiplist.SkipList, error) {
+			return NewLockFreeList(), nil
+		},
+	})
+}
@@ -0,0 +1,285 @@
+package rebuildsl
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+)
+
+const (
+	maxLevel    = 32
+	probability = 0.5
+)
+
+type rbNode struct {
+	key   skiplist.K
+	value skiplist.V
+	hits  int64 // 觀察到的存取次數
+	coin  int32 // 插入時擲出的隨機高度，疊加在頻率高度之上
+	next  []*rbNode
+}
+
+// RebuildSLList 依觀察到的存取頻率重新分配節點高度的 skip list。
+// 每次存取後以機率 p 重新計算該節點的高度；總存取次數每翻倍一次就全面重建一次，
+// 讓久未存取的節點隨著總次數增加而逐漸降低。
+type RebuildSLList struct {
+	head     *rbNode
+	level    int32
+	size     int32
+	m        int64     // 總存取次數
+	lastFull int64     // 上次全面重建時的 m
+	p        float64   // 每次存取後重新計算高度的機率
+	capLevel int32     // 節點高度上限
+	preds    []*rbNode // search 使用的暫存路徑
+}
+
+func newNode(key skiplist.K, value skiplist.V, level int32) *rbNode {
+	return &rbNode{
+		key:   key,
+		value: value,
+		next:  make([]*rbNode, level+1),
+	}
+}
+
+func NewRebuildSLList(p float64) *RebuildSLList {
+	return &RebuildSLList{
+		head:     newNode(-1, 0, maxLevel),
+		level:    1,
+		p:        p,
+		capLevel: maxLevel,
+		preds:    make([]*rbNode, maxLevel+1),
+	}
+}
+
+// find 搜尋 key，找到即提早返回
+func (sl *RebuildSLList) find(key skiplist.K) *rbNode {
+	cur := sl.head
+	for h := sl.level; h >= 0; h-- {
+		for cur.next[h] != nil && cur.next[h].key < key {
+			cur = cur.next[h]
+		}
+		if cur.next[h] != nil && cur.next[h].key == key {
+			return cur.next[h]
+		}
+	}
+	return nil
+}
+
+// search 由上而下搜尋 key，並將每層最後一個小於 key 的節點記錄在 sl.preds
+func (sl *RebuildSLList) search(key skiplist.K) *rbNode {
+	cur := sl.head
+	for h := sl.level; h >= 0; h-- {
+		for cur.next[h] != nil && cur.next[h].key < key {
+			cur = cur.next[h]
+		}
+		sl.preds[h] = cur
+	}
+	for h := sl.level + 1; h <= maxLevel; h++ {
+		sl.preds[h] = sl.head
+	}
+	if cur.next[0] != nil && cur.next[0].key == key {
+		return cur.next[0]
+	}
+	return nil
+}
+
+func (sl *RebuildSLList) randomLevel() int32 {
+	lvl := 0
+	for rand.Float64() < probability && lvl < maxLevel {
+		lvl++
+	}
+	return int32(lvl)
+}
+
+// targetLevel 依節點的觀察頻率計算高度，規則同 la.randomLevelWithNP：
+// np = size * hits / m，當 np >= 2^(l-1) 時保證升到第 l 層，之後再疊加插入時的隨機高度
+func (sl *RebuildSLList) targetLevel(nd *rbNode) int32 {
+	lvl := nd.coin
+	if sl.m > 0 {
+		np := float64(sl.size) * float64(nd.hits) / float64(sl.m)
+		if np >= 1 {
+			lvl += int32(math.Floor(math.Log2(np))) + 1
+		}
+	}
+	return min(lvl, sl.capLevel)
+}
+
+// access 記錄一次存取，並視情況調整節點高度或全面重建
+func (sl *RebuildSLList) access(nd *rbNode) {
+	nd.hits++
+	sl.m++
+	if sl.m >= 2*sl.lastFull {
+		sl.rebuild()
+		return
+	}
+	if rand.Float64() < sl.p {
+		sl.relevel(nd)
+	}
+}
+
+// relevel 將單一節點調整到 targetLevel 計算出的高度
+func (sl *RebuildSLList) relevel(nd *rbNode) {
+	want := sl.targetLevel(nd)
+	cur := int32(len(nd.next) - 1)
+	if want == cur {
+		return
+	}
+	sl.search(nd.key)
+	if want < cur {
+		for h := cur; h > want; h-- {
+			sl.preds[h].next[h] = nd.next[h]
+		}
+		nd.next = nd.next[:want+1]
+		sl.shrinkLevel()
+		return
+	}
+	for h := cur + 1; h <= want; h++ {
+		nd.next = append(nd.next, sl.preds[h].next[h])
+		sl.preds[h].next[h] = nd
+	}
+	sl.level = max(sl.level, want)
+}
+
+// rebuild 依目前的頻率重新計算所有節點高度，並以一次線性走訪重建各層連結
+func (sl *RebuildSLList) rebuild() {
+	last := sl.preds
+	for h := range last {
+		last[h] = sl.head
+	}
+	top := int32(1)
+	for nd := sl.head.next[0]; nd != nil; nd = nd.next[0] {
+		lvl := sl.targetLevel(nd)
+		if int(lvl) >= cap(nd.next) {
+			next := make([]*rbNode, lvl+1)
+			next[0] = nd.next[0]
+			nd.next = next
+		} else {
+			nd.next = nd.next[:lvl+1]
+		}
+		for h := int32(1); h <= lvl; h++ {
+			last[h].next[h] = nd
+			last[h] = nd
+		}
+		top = max(top, lvl)
+	}
+	for h := 1; h <= maxLevel; h++ {
+		last[h].next[h] = nil
+	}
+	sl.level = top
+	sl.lastFull = sl.m
+}
+
+// shrinkLevel 移除頂端已空的層級
+func (sl *RebuildSLList) shrinkLevel() {
+	for sl.level > 1 && sl.head.next[sl.level] == nil {
+		sl.level--
+	}
+}
+
+// ForceBalance 以 limit 為高度上限，依目前的頻率全面重建
+func (sl *RebuildSLList) ForceBalance(limit int) {
+	sl.capLevel = int32(max(0, min(limit, maxLevel)))
+	sl.rebuild()
+}
+
+func (sl *RebuildSLList) Put(key skiplist.K, value skiplist.V) (skiplist.V, bool) {
+	if nd := sl.find(key); nd != nil {
+		old := nd.value
+		nd.value = value
+		sl.access(nd)
+		return old, true
+	}
+	nd := newNode(key, value, 0)
+	nd.coin = sl.randomLevel()
+	nd.hits = 1
+	sl.m++
+	sl.size++
+
+	lvl := sl.targetLevel(nd)
+	if lvl > 0 {
+		nd.next = make([]*rbNode, lvl+1)
+	}
+	sl.search(key)
+	for h := int32(0); h <= lvl; h++ {
+		nd.next[h] = sl.preds[h].next[h]
+		sl.preds[h].next[h] = nd
+	}
+	sl.level = max(sl.level, lvl)
+	return 0, false
+}
+
+func (sl *RebuildSLList) Get(key skiplist.K) (skiplist.V, bool) {
+	nd := sl.find(key)
+	if nd == nil {
+		return 0, false
+	}
+	sl.access(nd)
+	return nd.value, true
+}
+
+func (sl *RebuildSLList) Contains(key skiplist.K) bool {
+	nd := sl.find(key)
+	if nd == nil {
+		return false
+	}
+	sl.access(nd)
+	return true
+}
+
+func (sl *RebuildSLList) Delete(key skiplist.K) (skiplist.V, bool) {
+	nd := sl.search(key)
+	if nd == nil {
+		return 0, false
+	}
+	for h := int32(len(nd.next) - 1); h >= 0; h-- {
+		sl.preds[h].next[h] = nd.next[h]
+	}
+	sl.size--
+	sl.shrinkLevel()
+	return nd.value, true
+}
+
+func (sl *RebuildSLList) Len() int {
+	return int(sl.size)
+}
+
+func (sl *RebuildSLList) GetHead() skiplist.Nodelike {
+	return sl.head
+}
+
+func (sl *RebuildSLList) GetMaxStats() (int, int) {
+	return int(sl.size), int(sl.level)
+}
+
+func (sl *RebuildSLList) Compare(a, b skiplist.K) int {
+	return cmp.Compare(a, b)
+}
+
+func (nd *rbNode) GetKey() skiplist.K {
+	return nd.key
+}
+
+func (nd *rbNode) GetValue() skiplist.V {
+	return nd.value
+}
+
+func (nd *rbNode) GetLevel() int32 {
+	return int32(len(nd.next) - 1)
+}
+
+func (nd *rbNode) GetNextAt(level int32) skiplist.Nodelike {
+	if level < 0 || level >= int32(len(nd.next)) {
+		return nil
+	}
+	if nd.next[level] == nil {
+		return nil
+	}
+	return nd.next[level]
+}
+
+// NextAt 回傳第 h 層的下一個節點，供 skiplist.LevelIterator 走訪
+func (nd *rbNode) NextAt(h int32) *rbNode {
+	return nd.next[h]
+}
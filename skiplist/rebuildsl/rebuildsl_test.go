@@ -0,0 +1,84 @@
+package rebuildsl
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Hakuto4838/SkipList.git/datastream"
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/analyTool"
+	"github.com/Hakuto4838/SkipList.git/skiplist/skiplisttest"
+)
+
+func TestRebuildSLListInterface(t *testing.T) {
+	var _ skiplist.SkipList = (*RebuildSLList)(nil)
+	var _ skiplist.Analyable = (*RebuildSLList)(nil)
+	var _ skiplist.Nodelike = (*rbNode)(nil)
+}
+
+func TestRebuildSLListBasic(t *testing.T) {
+	sl := NewRebuildSLList(0.5)
+	for i := 0; i < 100; i++ {
+		sl.Put(skiplist.K(i), skiplist.V(i*10))
+	}
+
+	for i := 0; i < 100; i++ {
+		if value, found := sl.Get(skiplist.K(i)); !found || value != skiplist.V(i*10) {
+			t.Errorf("Get(%d) = (%f, %v), want (%d, true)", i, value, found, i*10)
+		}
+	}
+
+	for i := 0; i < 100; i += 2 {
+		sl.Delete(skiplist.K(i))
+	}
+	for i := 0; i < 100; i++ {
+		if sl.Contains(skiplist.K(i)) != (i%2 == 1) {
+			t.Errorf("Contains(%d) = %v after delete", i, !(i%2 == 1))
+		}
+	}
+
+	if size, _ := sl.GetMaxStats(); size != 50 {
+		t.Errorf("size = %d, want 50", size)
+	}
+	if !analyTool.CheckStruct(sl) {
+		t.Error("CheckStruct failed")
+	}
+}
+
+func TestRebuildSLListForceBalance(t *testing.T) {
+	const N = 1000
+	data := datastream.NewZipfDataGenerator(N, 1.5, 1, 42)
+	keymap := data.GetKeyMap()
+
+	sl := NewRebuildSLList(0.1)
+	for k, v := range keymap {
+		sl.Put(k, v)
+	}
+	for range N * 10 {
+		sl.Get(skiplist.K(data.Next()))
+	}
+
+	before, _ := analyTool.AnalyzeStep(sl, keymap)
+	sl.ForceBalance(32)
+	after, _ := analyTool.AnalyzeStep(sl, keymap)
+	fmt.Printf("score before: %f, after ForceBalance: %f\n", before, after)
+
+	if !analyTool.CheckStruct(sl) {
+		t.Error("CheckStruct failed after ForceBalance")
+	}
+	for k := range keymap {
+		if !sl.Contains(k) {
+			t.Fatalf("key %d missing after ForceBalance", k)
+		}
+	}
+
+	sl.ForceBalance(4)
+	if _, level := sl.GetMaxStats(); level > 4 {
+		t.Errorf("level = %d after ForceBalance(4)", level)
+	}
+	analyTool.PrintSkipList(sl, 5, 10)
+}
+
+func TestRebuildSLListConformance(t *testing.T) {
+	skiplisttest.Run(t, func() skiplist.SkipList { return NewRebuildSLList(0.5) })
+}
@@ -0,0 +1,21 @@
+package rebuildsl
+
+import (
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/registry"
+)
+
+func init() {
+	registry.Register(registry.Impl{
+		Name:   "rebuild",
+		Usage:  "依觀察到的存取頻率重新分配高度的 skip list",
+		Params: []registry.Param{{Name: "p", Kind: registry.Float, Default: "0.1", Usage: "每次存取後重新計算高度的機率"}},
+		New: func(p registry.Params, env registry.Env) (skiplist.SkipList, error) {
+			prob, err := p.Probability("p")
+			if err != nil {
+				return nil, err
+			}
+			return NewRebuildSLList(prob), nil
+		},
+	})
+}
@@ -0,0 +1,357 @@
+// Package registry 以名稱登錄各 skip list 實作的建構函式、參數說明與預設值，
+// 讓命令列工具以 -impl name 與 -param name.key=value 選擇並設定實作
+package registry
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/syncsl"
+)
+
+// Kind 為參數值的型別
+type Kind int
+
+const (
+	Float    Kind = iota
+	Int           // 十進位整數
+	String        // 任意字串
+	ImplName      // 其他已登錄實作的名稱，其參數同樣由 Config 決定
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Float:
+		return "float"
+	case Int:
+		return "int"
+	case String:
+		return "string"
+	case ImplName:
+		return "impl"
+	default:
+		return "unknown"
+	}
+}
+
+// Param 描述實作的一個參數
+type Param struct {
+	Name    string
+	Kind    Kind
+	Default string
+	Usage   string
+}
+
+// Impl 為一個已登錄的實作
+type Impl struct {
+	Name   string
+	Usage  string
+	Params []Param
+	New    func(p Params, env Env) (skiplist.SkipList, error)
+
+	ThreadSafe bool // 可直接供多個 goroutine 同時使用
+	SharedRead bool // 查詢不改變結構，以 syncsl 包裝時可共用讀鎖
+}
+
+// SyncMode 回傳以 syncsl 包裝此實作時應使用的讀鎖模式
+func (i *Impl) SyncMode() syncsl.Mode {
+	if i.SharedRead {
+		return syncsl.SharedRead
+	}
+	return syncsl.ExclusiveRead
+}
+
+func (i *Impl) param(name string) (Param, bool) {
+	for _, p := range i.Params {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Param{}, false
+}
+
+var (
+	impls = map[string]*Impl{}
+	order []string
+)
+
+// Register 登錄一個實作，名稱重複、含有 '.' 或 ','、或預設值無法解析時 panic。
+// 各實作在自己套件的 init 中登錄，命令列工具以空白匯入選擇要提供的實作，
+// 登錄順序即 -impl all 的執行順序
+func Register(impl Impl) {
+	if impl.Name == "" || strings.ContainsAny(impl.Name, ".,=") {
+		panic(fmt.Sprintf("registry: invalid name %q", impl.Name))
+	}
+	if impl.New == nil {
+		panic("registry: nil constructor for " + impl.Name)
+	}
+	if _, dup := impls[impl.Name]; dup {
+		panic("registry: Register called twice for " + impl.Name)
+	}
+	for _, p := range impl.Params {
+		// ImplName 的預設值可能指向之後才登錄的實作，留到使用時檢查
+		if p.Kind == ImplName {
+			continue
+		}
+		if _, err := parseValue(p, p.Default); err != nil {
+			panic(fmt.Sprintf("registry: default of %s.%s: %v", impl.Name, p.Name, err))
+		}
+	}
+	impl.Params = slices.Clone(impl.Params)
+	impls[impl.Name] = &impl
+	order = append(order, impl.Name)
+}
+
+// Lookup 回傳名稱為 name 的實作
+func Lookup(name string) (*Impl, bool) {
+	impl, ok := impls[name]
+	return impl, ok
+}
+
+// Names 依登錄順序回傳所有實作的名稱
+func Names() []string {
+	return slices.Clone(order)
+}
+
+// ParseNames 解析 -impl，可為 "all"、空字串（等同 all）或逗號分隔的名稱，重複的名稱只保留第一個
+func ParseNames(s string) ([]string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "all" {
+		return Names(), nil
+	}
+	var out []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name == "" || slices.Contains(out, name) {
+			continue
+		}
+		if _, ok := impls[name]; !ok {
+			return nil, fmt.Errorf("unknown implementation %q (available: %s)", name, strings.Join(order, ","))
+		}
+		out = append(out, name)
+	}
+	if len(out) == 0 {
+		return Names(), nil
+	}
+	return out, nil
+}
+
+// Params 為已套用預設值並依 Kind 解析的參數
+type Params struct {
+	impl   string
+	values map[string]any
+}
+
+func (p Params) get(name string) any {
+	v, ok := p.values[name]
+	if !ok {
+		panic(fmt.Sprintf("registry: %s has no parameter %q", p.impl, name))
+	}
+	return v
+}
+
+// Float 回傳 Float 參數的值，參數不存在或型別不符時 panic
+func (p Params) Float(name string) float64 {
+	return p.get(name).(float64)
+}
+
+// Int 回傳 Int 參數的值，參數不存在或型別不符時 panic
+func (p Params) Int(name string) int {
+	return p.get(name).(int)
+}
+
+// String 回傳 String 或 ImplName 參數的值，參數不存在或型別不符時 panic
+func (p Params) String(name string) string {
+	return p.get(name).(string)
+}
+
+// Probability 回傳 Float 參數的值，不在 [0, 1] 之間時回傳錯誤
+func (p Params) Probability(name string) (float64, error) {
+	v := p.Float(name)
+	if v < 0 || v > 1 {
+		return 0, fmt.Errorf("%s must be in [0, 1], got %g", name, v)
+	}
+	return v, nil
+}
+
+func parseValue(p Param, s string) (any, error) {
+	switch p.Kind {
+	case Float:
+		return strconv.ParseFloat(s, 64)
+	case Int:
+		return strconv.Atoi(s)
+	case String:
+		return s, nil
+	case ImplName:
+		if _, ok := impls[s]; !ok {
+			return nil, fmt.Errorf("unknown implementation %q", s)
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unknown kind %d", p.Kind)
+	}
+}
+
+// formatValue 以標準格式輸出已解析的值，使 0.010 與 0.01 視為相同
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Env 為建構實作時由呼叫端提供的環境
+type Env struct {
+	Seed int64
+	Dist map[skiplist.K]float64 // 預期的存取分布，可為 nil
+
+	cfg   Config
+	chain []string // 正在建構的實作，用來偵測互相引用
+}
+
+// New 以相同的 Config 與環境建立另一個已登錄的實作，供組合其他實作者使用
+func (e Env) New(name string) (skiplist.SkipList, error) {
+	return e.cfg.new(name, e)
+}
+
+// Config 為 -param 指定的參數值，依實作名稱與參數名稱索引，未指定的參數使用預設值。
+// Config 實作 flag.Value，可重複使用 -param name.key=value
+type Config map[string]map[string]string
+
+// Set 解析並檢查 name.key=value
+func (c Config) Set(s string) error {
+	assign, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("want impl.key=value, got %q", s)
+	}
+	name, key, ok := strings.Cut(strings.TrimSpace(assign), ".")
+	if !ok {
+		return fmt.Errorf("want impl.key=value, got %q", s)
+	}
+	impl, found := impls[name]
+	if !found {
+		return fmt.Errorf("unknown implementation %q", name)
+	}
+	p, found := impl.param(key)
+	if !found {
+		return fmt.Errorf("%s has no parameter %q", name, key)
+	}
+	value = strings.TrimSpace(value)
+	if _, err := parseValue(p, value); err != nil {
+		return fmt.Errorf("%s.%s: %w", name, key, err)
+	}
+	if c[name] == nil {
+		c[name] = map[string]string{}
+	}
+	c[name][key] = value
+	return nil
+}
+
+func (c Config) String() string {
+	var parts []string
+	for _, name := range slices.Sorted(maps.Keys(c)) {
+		for _, key := range slices.Sorted(maps.Keys(c[name])) {
+			parts = append(parts, fmt.Sprintf("%s.%s=%s", name, key, c[name][key]))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// Clone 回傳 c 的深拷貝
+func (c Config) Clone() Config {
+	out := make(Config, len(c))
+	for name, params := range c {
+		out[name] = maps.Clone(params)
+	}
+	return out
+}
+
+// Params 回傳 name 套用預設值與 c 之後的參數
+func (c Config) Params(name string) (Params, error) {
+	impl, ok := impls[name]
+	if !ok {
+		return Params{}, fmt.Errorf("unknown implementation %q", name)
+	}
+	p := Params{impl: name, values: make(map[string]any, len(impl.Params))}
+	for _, param := range impl.Params {
+		s := param.Default
+		if v, ok := c[name][param.Name]; ok {
+			s = v
+		}
+		v, err := parseValue(param, s)
+		if err != nil {
+			return Params{}, fmt.Errorf("%s.%s: %w", name, param.Name, err)
+		}
+		p.values[param.Name] = v
+	}
+	return p, nil
+}
+
+// New 以 c 的參數建立名稱為 name 的實作
+func (c Config) New(name string, env Env) (skiplist.SkipList, error) {
+	env.chain = nil
+	return c.new(name, env)
+}
+
+func (c Config) new(name string, env Env) (skiplist.SkipList, error) {
+	if slices.Contains(env.chain, name) {
+		return nil, fmt.Errorf("%s refers to itself through %s", name, strings.Join(env.chain, " -> "))
+	}
+	p, err := c.Params(name)
+	if err != nil {
+		return nil, err
+	}
+	env.cfg = c
+	env.chain = append(slices.Clip(env.chain), name)
+	sl, err := impls[name].New(p, env)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return sl, nil
+}
+
+// Describe 回傳 name 實際使用的所有參數，ImplName 參數所指實作的參數以 "key." 為前綴展開
+func (c Config) Describe(name string) map[string]string {
+	out := map[string]string{}
+	c.describe(name, "", out, nil)
+	return out
+}
+
+func (c Config) describe(name, prefix string, out map[string]string, chain []string) {
+	if slices.Contains(chain, name) {
+		return
+	}
+	p, err := c.Params(name)
+	if err != nil {
+		return
+	}
+	chain = append(chain, name)
+	for _, param := range impls[name].Params {
+		v := p.values[param.Name]
+		out[prefix+param.Name] = formatValue(v)
+		if param.Kind == ImplName {
+			c.describe(v.(string), prefix+param.Name+".", out, chain)
+		}
+	}
+}
+
+// PrintUsage 列出所有實作與其參數，供命令列工具的 -h 使用
+func PrintUsage(w io.Writer) {
+	fmt.Fprintln(w, "implementations (-impl) and parameters (-param impl.key=value):")
+	for _, name := range order {
+		impl := impls[name]
+		fmt.Fprintf(w, "  %s\t%s\n", name, impl.Usage)
+		for _, p := range impl.Params {
+			fmt.Fprintf(w, "      %s.%s %s\t%s (default %s)\n", name, p.Name, p.Kind, p.Usage, p.Default)
+		}
+	}
+}
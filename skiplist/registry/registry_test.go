@@ -0,0 +1,115 @@
+package registry_test
+
+import (
+	"flag"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/Tlist"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/basic"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/falldown"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/gravity"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/la"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/lockfree"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/rebuildsl"
+	"github.com/Hakuto4838/SkipList.git/skiplist/registry"
+	"github.com/Hakuto4838/SkipList.git/skiplist/sharded"
+	_ "github.com/Hakuto4838/SkipList.git/skiplist/splay"
+)
+
+// 每個內建實作以預設參數建立後都能正常存取
+func TestBuiltinDefaults(t *testing.T) {
+	dist := map[skiplist.K]float64{}
+	for k := skiplist.K(0); k < 100; k++ {
+		dist[k] = 0.01
+	}
+	cfg := registry.Config{}
+	for _, name := range registry.Names() {
+		sl, err := cfg.New(name, registry.Env{Seed: 1, Dist: dist})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for k := range skiplist.K(100) {
+			sl.Put(k, skiplist.V(k))
+		}
+		for k := range skiplist.K(100) {
+			if v, ok := sl.Get(k); !ok || v != skiplist.V(k) {
+				t.Fatalf("%s: Get(%d) = %v, %v", name, k, v, ok)
+			}
+		}
+		if sl.Len() != 100 {
+			t.Fatalf("%s: Len() = %d", name, sl.Len())
+		}
+	}
+}
+
+func TestParseNames(t *testing.T) {
+	got, err := registry.ParseNames(" splay, Basic ,splay,")
+	if err != nil || !slices.Equal(got, []string{"splay", "basic"}) {
+		t.Fatalf("ParseNames = %v, %v", got, err)
+	}
+	if got, _ := registry.ParseNames("all"); !slices.Equal(got, registry.Names()) {
+		t.Fatalf("ParseNames(all) = %v", got)
+	}
+	if _, err := registry.ParseNames("basic,nope"); err == nil {
+		t.Fatal("unknown name accepted")
+	}
+}
+
+func TestConfigSet(t *testing.T) {
+	cfg := registry.Config{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(cfg, "param", "")
+	if err := fs.Parse([]string{"-param", "splay.p=0.5", "-param", "sharded.base=splay", "-param", "sharded.n=3"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, bad := range []string{"splay.p", "p=1", "nope.p=1", "splay.q=1", "splay.p=x", "sharded.n=1.5", "sharded.base=nope"} {
+		if err := cfg.Set(bad); err == nil {
+			t.Errorf("Set(%q) accepted", bad)
+		}
+	}
+
+	p, err := cfg.Params("splay")
+	if err != nil || p.Float("p") != 0.5 {
+		t.Fatalf("splay p = %v, %v", p.Float("p"), err)
+	}
+	want := map[string]string{"n": "3", "base": "splay", "base.p": "0.5"}
+	if got := cfg.Describe("sharded"); !maps.Equal(got, want) {
+		t.Fatalf("Describe(sharded) = %v, want %v", got, want)
+	}
+	if got := (registry.Config{}).Describe("gravity"); !maps.Equal(got, map[string]string{"z": "1.8", "threshold": "0.5"}) {
+		t.Fatalf("Describe(gravity) = %v", got)
+	}
+
+	sl, err := cfg.New("sharded", registry.Env{Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 沒有分布時只有一個分片
+	if n := sl.(*sharded.ShardedSkipList[skiplist.K, skiplist.V]).Shards(); n != 1 {
+		t.Fatalf("got %d shards", n)
+	}
+	// 分片依 base 的參數建立
+	cfg.Set("splay.p=2")
+	if _, err := cfg.New("sharded", registry.Env{Seed: 1}); err == nil {
+		t.Fatal("invalid splay.p accepted by the shards")
+	}
+}
+
+func TestConstructorErrors(t *testing.T) {
+	for _, param := range []string{"splay.p=2", "gravity.z=1", "sharded.n=0", "sharded.base=sharded", "tlist.span=0"} {
+		cfg := registry.Config{}
+		if err := cfg.Set(param); err != nil {
+			t.Fatal(err)
+		}
+		name, _, _ := strings.Cut(param, ".")
+		if _, err := cfg.New(name, registry.Env{}); err == nil {
+			t.Errorf("%s: New succeeded", param)
+		}
+	}
+}
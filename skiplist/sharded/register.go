@@ -0,0 +1,40 @@
+package sharded
+
+import (
+	"fmt"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/registry"
+)
+
+func init() {
+	registry.Register(registry.Impl{
+		Name:       "sharded",
+		Usage:      "依分布切成機率相近的 key 區間，每個區間各自加鎖",
+		ThreadSafe: true,
+		Params: []registry.Param{
+			{Name: "n", Kind: registry.Int, Default: "8", Usage: "分片數"},
+			{Name: "base", Kind: registry.ImplName, Default: "basic", Usage: "每個分片使用的實作，其參數以 -param <base>.key=value 指定"},
+		},
+		New: func(p registry.Params, env registry.Env) (skiplist.SkipList, error) {
+			n := p.Int("n")
+			if n < 1 {
+				return nil, fmt.Errorf("n must be >= 1, got %d", n)
+			}
+			base, _ := registry.Lookup(p.String("base"))
+			// 分片在建構時依序建立，第一個錯誤即為結果
+			var shardErr error
+			sl := NewShardedSkipList(BoundsFromDist(env.Dist, n), func() skiplist.SkipList {
+				shard, err := env.New(base.Name)
+				if err != nil && shardErr == nil {
+					shardErr = err
+				}
+				return shard
+			}, base.SyncMode())
+			if shardErr != nil {
+				return nil, shardErr
+			}
+			return sl, nil
+		},
+	})
+}
@@ -0,0 +1,296 @@
+package sharded
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/syncsl"
+)
+
+// ShardedSkipList 依 key 範圍把資料分到多個分片，每個分片各自持有一把鎖，
+// 落在不同分片的寫入不會互相等待。分片之間的 key 範圍互不重疊且依序排列，
+// 因此依序走訪只需把各分片的結果接起來。
+//
+// Len、Min、PopMin 等跨分片的操作逐一詢問各分片，不是整體的原子快照。
+type ShardedSkipList[K cmp.Ordered, V any] struct {
+	bounds []K // bounds[i] 為第 i+1 個分片的最小 key，長度為分片數減一
+	shards []*syncsl.SyncSkipList[K, V]
+}
+
+// NewShardedSkipList 以遞增的 bounds 切出 len(bounds)+1 個分片，每個分片由 newShard 建立，
+// mode 決定分片的讀取鎖，規則與 syncsl 相同
+func NewShardedSkipList(bounds []skiplist.K, newShard func() skiplist.SkipList, mode syncsl.Mode) *ShardedSkipList[skiplist.K, skiplist.V] {
+	return NewShardedSkipListOf(bounds, newShard, mode)
+}
+
+// NewShardedSkipListOf 建立任意可排序 key 的 ShardedSkipList
+func NewShardedSkipListOf[K cmp.Ordered, V any](bounds []K, newShard func() skiplist.SkipListOf[K, V], mode syncsl.Mode) *ShardedSkipList[K, V] {
+	if !slices.IsSorted(bounds) {
+		panic("sharded: bounds must be sorted")
+	}
+	bounds = slices.Compact(slices.Clone(bounds))
+	shards := make([]*syncsl.SyncSkipList[K, V], len(bounds)+1)
+	for i := range shards {
+		shards[i] = syncsl.NewSyncSkipList(newShard(), mode)
+	}
+	return &ShardedSkipList[K, V]{bounds: bounds, shards: shards}
+}
+
+// BoundsFromDist 依 key 的存取機率（如 BenchFile.Dist）切出 n 個機率總和相近的分片，
+// 讓存取較平均地分散到各分片的鎖上
+func BoundsFromDist(dist map[skiplist.K]float64, n int) []skiplist.K {
+	if n <= 1 || len(dist) == 0 {
+		return nil
+	}
+	keys := make([]skiplist.K, 0, len(dist))
+	total := 0.0
+	for k, p := range dist {
+		keys = append(keys, k)
+		total += p
+	}
+	slices.Sort(keys)
+
+	bounds := make([]skiplist.K, 0, n-1)
+	acc := 0.0
+	eps := total * 1e-9 // 吸收累加的浮點誤差
+	for _, k := range keys {
+		// k 之前的累積機率已達下一個分位點時，k 成為新分片的第一個 key
+		if acc+eps >= total*float64(len(bounds)+1)/float64(n) {
+			bounds = append(bounds, k)
+			if len(bounds) == n-1 {
+				break
+			}
+		}
+		acc += dist[k]
+	}
+	return bounds
+}
+
+// shardIndex 回傳 key 所屬分片的索引
+func (sl *ShardedSkipList[K, V]) shardIndex(key K) int {
+	i, found := slices.BinarySearch(sl.bounds, key)
+	if found {
+		i++
+	}
+	return i
+}
+
+func (sl *ShardedSkipList[K, V]) shard(key K) *syncsl.SyncSkipList[K, V] {
+	return sl.shards[sl.shardIndex(key)]
+}
+
+// Shards 回傳分片數
+func (sl *ShardedSkipList[K, V]) Shards() int {
+	return len(sl.shards)
+}
+
+func (sl *ShardedSkipList[K, V]) Contains(key K) bool {
+	return sl.shard(key).Contains(key)
+}
+
+func (sl *ShardedSkipList[K, V]) Get(key K) (V, bool) {
+	return sl.shard(key).Get(key)
+}
+
+func (sl *ShardedSkipList[K, V]) Put(key K, value V) (V, bool) {
+	return sl.shard(key).Put(key, value)
+}
+
+func (sl *ShardedSkipList[K, V]) Delete(key K) (V, bool) {
+	return sl.shard(key).Delete(key)
+}
+
+func (sl *ShardedSkipList[K, V]) Len() int {
+	n := 0
+	for _, s := range sl.shards {
+		n += s.Len()
+	}
+	return n
+}
+
+// GetHead 分片沒有共同的頭節點，回傳 nil，因此 ShardedSkipList 不提供 Analyable
+func (sl *ShardedSkipList[K, V]) GetHead() skiplist.NodelikeOf[K, V] {
+	return nil
+}
+
+func (sl *ShardedSkipList[K, V]) Iterator() skiplist.IteratorOf[K, V] {
+	return &shardIterator[K, V]{sl: sl, idx: -1}
+}
+
+// Range 依序對與 [lo, hi) 重疊的分片呼叫 Range，每個分片在走訪期間持有自己的讀鎖，
+// fn 內不可再修改同一個分片
+func (sl *ShardedSkipList[K, V]) Range(lo, hi K, fn func(key K, value V) bool) {
+	stopped := false
+	for i := sl.shardIndex(lo); i < len(sl.shards) && !stopped; i++ {
+		if i > 0 && sl.bounds[i-1] >= hi {
+			return
+		}
+		sl.shards[i].Range(lo, hi, func(key K, value V) bool {
+			if !fn(key, value) {
+				stopped = true
+			}
+			return !stopped
+		})
+	}
+}
+
+func (sl *ShardedSkipList[K, V]) All() iter.Seq2[K, V] {
+	return skiplist.AllOf(sl.Iterator)
+}
+
+func (sl *ShardedSkipList[K, V]) Backward() iter.Seq2[K, V] {
+	return skiplist.BackwardOf(sl.Iterator)
+}
+
+// before 在 key 所屬分片以 find 查詢，找不到時改取前面分片的最大值
+func (sl *ShardedSkipList[K, V]) before(key K, find func(s *syncsl.SyncSkipList[K, V]) (K, V, bool)) (K, V, bool) {
+	i := sl.shardIndex(key)
+	if k, v, ok := find(sl.shards[i]); ok {
+		return k, v, ok
+	}
+	for i--; i >= 0; i-- {
+		if k, v, ok := sl.shards[i].Max(); ok {
+			return k, v, ok
+		}
+	}
+	var k K
+	var v V
+	return k, v, false
+}
+
+// after 在 key 所屬分片以 find 查詢，找不到時改取後面分片的最小值
+func (sl *ShardedSkipList[K, V]) after(key K, find func(s *syncsl.SyncSkipList[K, V]) (K, V, bool)) (K, V, bool) {
+	i := sl.shardIndex(key)
+	if k, v, ok := find(sl.shards[i]); ok {
+		return k, v, ok
+	}
+	for i++; i < len(sl.shards); i++ {
+		if k, v, ok := sl.shards[i].Min(); ok {
+			return k, v, ok
+		}
+	}
+	var k K
+	var v V
+	return k, v, false
+}
+
+func (sl *ShardedSkipList[K, V]) Floor(key K) (K, V, bool) {
+	return sl.before(key, func(s *syncsl.SyncSkipList[K, V]) (K, V, bool) { return s.Floor(key) })
+}
+
+func (sl *ShardedSkipList[K, V]) Ceiling(key K) (K, V, bool) {
+	return sl.after(key, func(s *syncsl.SyncSkipList[K, V]) (K, V, bool) { return s.Ceiling(key) })
+}
+
+func (sl *ShardedSkipList[K, V]) Predecessor(key K) (K, V, bool) {
+	return sl.before(key, func(s *syncsl.SyncSkipList[K, V]) (K, V, bool) { return s.Predecessor(key) })
+}
+
+func (sl *ShardedSkipList[K, V]) Successor(key K) (K, V, bool) {
+	return sl.after(key, func(s *syncsl.SyncSkipList[K, V]) (K, V, bool) { return s.Successor(key) })
+}
+
+// firstOf 依序對各分片呼叫 op，回傳第一個成功的結果
+func firstOf[K cmp.Ordered, V any](shards iter.Seq[*syncsl.SyncSkipList[K, V]], op func(s *syncsl.SyncSkipList[K, V]) (K, V, bool)) (K, V, bool) {
+	for s := range shards {
+		if k, v, ok := op(s); ok {
+			return k, v, ok
+		}
+	}
+	var k K
+	var v V
+	return k, v, false
+}
+
+func (sl *ShardedSkipList[K, V]) Min() (K, V, bool) {
+	return firstOf(slices.Values(sl.shards), (*syncsl.SyncSkipList[K, V]).Min)
+}
+
+func (sl *ShardedSkipList[K, V]) Max() (K, V, bool) {
+	return firstOf(reversed(sl.shards), (*syncsl.SyncSkipList[K, V]).Max)
+}
+
+// PopMin 由第一個非空的分片取出最小元素，多個消費者不會取得同一個元素
+func (sl *ShardedSkipList[K, V]) PopMin() (K, V, bool) {
+	return firstOf(slices.Values(sl.shards), (*syncsl.SyncSkipList[K, V]).PopMin)
+}
+
+// PopMax 由最後一個非空的分片取出最大元素
+func (sl *ShardedSkipList[K, V]) PopMax() (K, V, bool) {
+	return firstOf(reversed(sl.shards), (*syncsl.SyncSkipList[K, V]).PopMax)
+}
+
+// reversed 反向走訪 s 的元素
+func reversed[E any](s []E) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for _, e := range slices.Backward(s) {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// shardIterator 在目前分片的迭代器走到盡頭時，接到相鄰分片的第一個或最後一個元素
+type shardIterator[K cmp.Ordered, V any] struct {
+	sl  *ShardedSkipList[K, V]
+	idx int
+	it  skiplist.IteratorOf[K, V]
+}
+
+// at 切換到第 i 個分片
+func (it *shardIterator[K, V]) at(i int) skiplist.IteratorOf[K, V] {
+	it.idx = i
+	it.it = it.sl.shards[i].Iterator()
+	return it.it
+}
+
+// forward 目前的分片已走完時，往後找第一個非空的分片
+func (it *shardIterator[K, V]) forward() {
+	for !it.it.Valid() && it.idx+1 < len(it.sl.shards) {
+		it.at(it.idx + 1).SeekToFirst()
+	}
+}
+
+// backward 目前的分片已走完時，往前找最後一個非空的分片
+func (it *shardIterator[K, V]) backward() {
+	for !it.it.Valid() && it.idx > 0 {
+		it.at(it.idx - 1).SeekToLast()
+	}
+}
+
+func (it *shardIterator[K, V]) Valid() bool { return it.it != nil && it.it.Valid() }
+func (it *shardIterator[K, V]) Key() K      { return it.it.Key() }
+func (it *shardIterator[K, V]) Value() V    { return it.it.Value() }
+
+func (it *shardIterator[K, V]) Next() {
+	it.it.Next()
+	it.forward()
+}
+
+func (it *shardIterator[K, V]) Prev() {
+	it.it.Prev()
+	it.backward()
+}
+
+func (it *shardIterator[K, V]) Seek(key K) {
+	it.at(it.sl.shardIndex(key)).Seek(key)
+	it.forward()
+}
+
+func (it *shardIterator[K, V]) SeekBefore(key K) {
+	it.at(it.sl.shardIndex(key)).SeekBefore(key)
+	it.backward()
+}
+
+func (it *shardIterator[K, V]) SeekToFirst() {
+	it.at(0).SeekToFirst()
+	it.forward()
+}
+
+func (it *shardIterator[K, V]) SeekToLast() {
+	it.at(len(it.sl.shards) - 1).SeekToLast()
+	it.backward()
+}
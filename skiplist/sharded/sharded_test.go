@@ -0,0 +1,233 @@
+package sharded
+
+import (
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/basic"
+	"github.com/Hakuto4838/SkipList.git/skiplist/skiplisttest"
+	"github.com/Hakuto4838/SkipList.git/skiplist/splay"
+	"github.com/Hakuto4838/SkipList.git/skiplist/syncsl"
+)
+
+func TestShardedSkipListInterface(t *testing.T) {
+	var _ skiplist.SkipList = (*ShardedSkipList[skiplist.K, skiplist.V])(nil)
+}
+
+func newBasic() skiplist.SkipList { return basic.NewBasicSkipList(1) }
+
+func newBasicShards(bounds []skiplist.K) *ShardedSkipList[skiplist.K, skiplist.V] {
+	return NewShardedSkipList(bounds, newBasic, syncsl.SharedRead)
+}
+
+func TestShardedSkipListConformance(t *testing.T) {
+	skiplisttest.Run(t, func() skiplist.SkipList { return newBasicShards([]skiplist.K{10, 20, 30, 40, 90}) })
+}
+
+// 與單一的 basic skip list 對照，包含空分片與跨分片的查詢
+func TestShardedSkipListMatchesBasic(t *testing.T) {
+	sl := newBasicShards([]skiplist.K{10, 20, 30, 40, 90})
+	ref := basic.NewBasicSkipList(2)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		// 只使用 [0, 80)，讓最後兩個分片保持空的
+		key := skiplist.K(r.Intn(40))
+		if key >= 20 {
+			key += 40
+		}
+		switch r.Intn(3) {
+		case 0:
+			gotOld, gotOK := sl.Put(key, skiplist.V(i))
+			wantOld, wantOK := ref.Put(key, skiplist.V(i))
+			if gotOld != wantOld || gotOK != wantOK {
+				t.Fatalf("Put(%d) = (%f, %v), want (%f, %v)", key, gotOld, gotOK, wantOld, wantOK)
+			}
+		case 1:
+			gotV, gotOK := sl.Delete(key)
+			wantV, wantOK := ref.Delete(key)
+			if gotV != wantV || gotOK != wantOK {
+				t.Fatalf("Delete(%d) = (%f, %v), want (%f, %v)", key, gotV, gotOK, wantV, wantOK)
+			}
+		default:
+			gotV, gotOK := sl.Get(key)
+			wantV, wantOK := ref.Get(key)
+			if gotV != wantV || gotOK != wantOK {
+				t.Fatalf("Get(%d) = (%f, %v), want (%f, %v)", key, gotV, gotOK, wantV, wantOK)
+			}
+		}
+	}
+	if sl.Len() != ref.Len() {
+		t.Fatalf("Len() = %d, want %d", sl.Len(), ref.Len())
+	}
+
+	type pair struct {
+		k skiplist.K
+		v skiplist.V
+	}
+	collect := func(seq func(yield func(skiplist.K, skiplist.V) bool)) []pair {
+		var out []pair
+		for k, v := range seq {
+			out = append(out, pair{k, v})
+		}
+		return out
+	}
+	if got, want := collect(sl.All()), collect(ref.All()); !slices.Equal(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+	if got, want := collect(sl.Backward()), collect(ref.Backward()); !slices.Equal(got, want) {
+		t.Errorf("Backward() = %v, want %v", got, want)
+	}
+
+	for key := skiplist.K(-5); key < 100; key++ {
+		var got, want []pair
+		sl.Range(key, key+25, func(k skiplist.K, v skiplist.V) bool { got = append(got, pair{k, v}); return true })
+		ref.Range(key, key+25, func(k skiplist.K, v skiplist.V) bool { want = append(want, pair{k, v}); return true })
+		if !slices.Equal(got, want) {
+			t.Fatalf("Range(%d, %d) = %v, want %v", key, key+25, got, want)
+		}
+
+		nearest := []struct {
+			name      string
+			got, want func(skiplist.K) (skiplist.K, skiplist.V, bool)
+		}{
+			{"Floor", sl.Floor, ref.Floor},
+			{"Ceiling", sl.Ceiling, ref.Ceiling},
+			{"Predecessor", sl.Predecessor, ref.Predecessor},
+			{"Successor", sl.Successor, ref.Successor},
+		}
+		for _, n := range nearest {
+			gk, gv, gok := n.got(key)
+			wk, wv, wok := n.want(key)
+			if gok != wok || (wok && (gk != wk || gv != wv)) {
+				t.Errorf("%s(%d) = (%d, %f, %v), want (%d, %f, %v)", n.name, key, gk, gv, gok, wk, wv, wok)
+			}
+		}
+	}
+
+	// [20, 60) 之間的分片都是空的，Next 與 Prev 必須直接跨過
+	it := sl.Iterator()
+	it.Seek(20)
+	if k, _, _ := ref.Ceiling(20); !it.Valid() || it.Key() != k {
+		t.Fatalf("Seek(20) valid = %v, want key %d", it.Valid(), k)
+	}
+	it.Prev()
+	if k, _, _ := ref.Predecessor(20); !it.Valid() || it.Key() != k {
+		t.Errorf("Prev() across empty shards valid = %v, want key %d", it.Valid(), k)
+	}
+}
+
+func TestShardedSkipListRangeStop(t *testing.T) {
+	sl := newBasicShards([]skiplist.K{5, 10})
+	for k := skiplist.K(0); k < 15; k++ {
+		sl.Put(k, skiplist.V(k))
+	}
+	var got []skiplist.K
+	sl.Range(3, 15, func(k skiplist.K, v skiplist.V) bool {
+		got = append(got, k)
+		return k < 7
+	})
+	if want := []skiplist.K{3, 4, 5, 6, 7}; !slices.Equal(got, want) {
+		t.Errorf("Range stopped after %v, want %v", got, want)
+	}
+}
+
+func TestShardedSkipListPriorityQueue(t *testing.T) {
+	sl := newBasicShards([]skiplist.K{10, 20})
+	if _, _, ok := sl.PopMin(); ok {
+		t.Error("PopMin() on empty list ok = true, want false")
+	}
+	for _, k := range []skiplist.K{25, 3, 14, 8, 21} {
+		sl.Put(k, skiplist.V(k))
+	}
+	if k, _, ok := sl.Min(); !ok || k != 3 {
+		t.Errorf("Min() = (%d, %v), want (3, true)", k, ok)
+	}
+	if k, _, ok := sl.Max(); !ok || k != 25 {
+		t.Errorf("Max() = (%d, %v), want (25, true)", k, ok)
+	}
+	var popped []skiplist.K
+	for {
+		k, _, ok := sl.PopMin()
+		if !ok {
+			break
+		}
+		popped = append(popped, k)
+	}
+	if want := []skiplist.K{3, 8, 14, 21, 25}; !slices.Equal(popped, want) {
+		t.Errorf("PopMin() order = %v, want %v", popped, want)
+	}
+}
+
+func TestBoundsFromDist(t *testing.T) {
+	dist := map[skiplist.K]float64{}
+	for k := skiplist.K(0); k < 100; k++ {
+		dist[k] = 0.01
+	}
+	if got, want := BoundsFromDist(dist, 4), []skiplist.K{25, 50, 75}; !slices.Equal(got, want) {
+		t.Errorf("BoundsFromDist(uniform, 4) = %v, want %v", got, want)
+	}
+
+	// 機率集中在少數 key 時，熱門的 key 應各自落在較小的分片
+	skewed := map[skiplist.K]float64{0: 0.4, 1: 0.3, 2: 0.1, 3: 0.1, 4: 0.05, 5: 0.05}
+	if got, want := BoundsFromDist(skewed, 3), []skiplist.K{1, 2}; !slices.Equal(got, want) {
+		t.Errorf("BoundsFromDist(skewed, 3) = %v, want %v", got, want)
+	}
+	if got := BoundsFromDist(dist, 1); got != nil {
+		t.Errorf("BoundsFromDist(dist, 1) = %v, want nil", got)
+	}
+	if got := newBasicShards(BoundsFromDist(dist, 8)).Shards(); got != 8 {
+		t.Errorf("Shards() = %d, want 8", got)
+	}
+}
+
+func TestShardedSkipListConcurrent(t *testing.T) {
+	sl := NewShardedSkipList([]skiplist.K{50, 100, 150}, func() skiplist.SkipList { return splay.NewSplayList(0.1) }, syncsl.ExclusiveRead)
+	const goroutines = 16
+	const opsPerGoroutine = 2000
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(id)))
+			for i := 0; i < opsPerGoroutine; i++ {
+				key := skiplist.K(r.Intn(200))
+				switch r.Intn(5) {
+				case 0:
+					sl.Put(key, skiplist.V(key))
+				case 1:
+					sl.Delete(key)
+				case 2:
+					sl.Floor(key)
+				case 3:
+					prev := skiplist.K(-1)
+					for k := range sl.All() {
+						if k <= prev {
+							t.Errorf("All() yielded %d after %d", k, prev)
+						}
+						prev = k
+						if k > key {
+							break
+						}
+					}
+				default:
+					if v, found := sl.Get(key); found && v != skiplist.V(key) {
+						t.Errorf("Get(%d) = %f, want %d", key, v, key)
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	n := 0
+	for range sl.All() {
+		n++
+	}
+	if n != sl.Len() {
+		t.Errorf("All() yielded %d keys, Len() = %d", n, sl.Len())
+	}
+}
@@ -0,0 +1,259 @@
+// Package skiplisttest 提供各 skip list 實作共用的一致性測試，
+// 每個實作套件以 Run 傳入自己的建構函式，實作特有的行為（墓碑、升階等）仍在各自的測試中檢查
+package skiplisttest
+
+import (
+	"testing"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+)
+
+// Run 以 newList 建立的空串列執行所有一致性測試，每項測試各自建立新的串列
+func Run(t *testing.T, newList func() skiplist.SkipList) {
+	t.Helper()
+	t.Run("Iterator", func(t *testing.T) { testIterator(t, newList) })
+	t.Run("IteratorEdges", func(t *testing.T) { testIteratorEdges(t, newList) })
+	t.Run("Nearest", func(t *testing.T) { testNearest(t, newList) })
+	t.Run("PutDeleteResults", func(t *testing.T) { testPutDeleteResults(t, newList) })
+	t.Run("PriorityQueue", func(t *testing.T) { testPriorityQueue(t, newList) })
+}
+
+func testIterator(t *testing.T, newList func() skiplist.SkipList) {
+	sl := newList()
+	want := map[skiplist.K]bool{}
+	for i := 0; i < 100; i++ {
+		sl.Put(skiplist.K(i*2), skiplist.V(i))
+		want[skiplist.K(i*2)] = true
+	}
+	for i := 0; i < 100; i += 3 {
+		sl.Delete(skiplist.K(i * 2))
+		delete(want, skiplist.K(i*2))
+	}
+	// 反覆存取讓自我調整的結構變動，走訪結果不應受影響
+	for r := 0; r < 500; r++ {
+		sl.Get(skiplist.K(r % 7 * 2))
+	}
+
+	var keys []skiplist.K
+	for k, v := range sl.All() {
+		if !want[k] || v != skiplist.V(k/2) {
+			t.Fatalf("All() yielded (%d, %f), want live key with value %d", k, v, k/2)
+		}
+		if len(keys) > 0 && k <= keys[len(keys)-1] {
+			t.Fatalf("All() yielded %d after %d", k, keys[len(keys)-1])
+		}
+		keys = append(keys, k)
+	}
+	if len(keys) != len(want) {
+		t.Fatalf("All() yielded %d keys, want %d", len(keys), len(want))
+	}
+	i := len(keys) - 1
+	for k := range sl.Backward() {
+		if k != keys[i] {
+			t.Fatalf("Backward() = %d at %d, want %d", k, i, keys[i])
+		}
+		i--
+	}
+
+	it := sl.Iterator()
+	it.Seek(5) // 6 已刪除
+	if !it.Valid() || it.Key() != 8 {
+		t.Fatalf("Seek(5) invalid or at wrong key, want 8")
+	}
+	it.Prev()
+	if !it.Valid() || it.Key() != 4 {
+		t.Fatalf("Prev() from 8 invalid or at wrong key, want 4")
+	}
+	it.SeekToLast()
+	if !it.Valid() || it.Key() != keys[len(keys)-1] {
+		t.Fatalf("SeekToLast() invalid or at wrong key, want %d", keys[len(keys)-1])
+	}
+	it.Next()
+	if it.Valid() {
+		t.Errorf("Next() from last = %d, want exhausted", it.Key())
+	}
+
+	var got []skiplist.K
+	sl.Range(10, 20, func(k skiplist.K, v skiplist.V) bool {
+		got = append(got, k)
+		return true
+	})
+	if len(got) != 3 || got[0] != 10 || got[1] != 14 || got[2] != 16 {
+		t.Errorf("Range(10, 20) = %v, want [10 14 16]", got)
+	}
+}
+
+// testIteratorEdges 檢查迭代器在兩端與空串列上的行為，以及 Range 提前停止
+func testIteratorEdges(t *testing.T, newList func() skiplist.SkipList) {
+	sl := newList()
+	for i := 0; i < 20; i++ {
+		sl.Put(skiplist.K(i*2), skiplist.V(i))
+	}
+	sl.Delete(10)
+
+	var keys []skiplist.K
+	for k := range sl.All() {
+		keys = append(keys, k)
+	}
+	if len(keys) != 19 || keys[0] != 0 || keys[18] != 38 {
+		t.Fatalf("All() = %v, want 19 keys from 0 to 38", keys)
+	}
+	for i := 1; i < len(keys); i++ {
+		if keys[i] <= keys[i-1] || keys[i] == 10 {
+			t.Fatalf("All() = %v, want ascending keys without 10", keys)
+		}
+	}
+
+	i := len(keys) - 1
+	for k := range sl.Backward() {
+		if k != keys[i] {
+			t.Fatalf("Backward() key %d = %d, want %d", len(keys)-1-i, k, keys[i])
+		}
+		i--
+	}
+
+	it := sl.Iterator()
+	it.Seek(9)
+	if !it.Valid() || it.Key() != 12 {
+		t.Fatalf("Seek(9) at %v, want 12", it.Key())
+	}
+	it.Prev()
+	if !it.Valid() || it.Key() != 8 || it.Value() != 4 {
+		t.Fatalf("Prev() at (%v, %v), want (8, 4)", it.Key(), it.Value())
+	}
+	it.Seek(39)
+	if it.Valid() {
+		t.Errorf("Seek(39) valid at %v, want exhausted", it.Key())
+	}
+	it.SeekToFirst()
+	it.Prev()
+	if it.Valid() {
+		t.Errorf("Prev() from first valid at %v, want exhausted", it.Key())
+	}
+
+	var got []skiplist.K
+	sl.Range(6, 16, func(k skiplist.K, v skiplist.V) bool {
+		got = append(got, k)
+		return len(got) < 3
+	})
+	if len(got) != 3 || got[0] != 6 || got[1] != 8 || got[2] != 12 {
+		t.Errorf("Range(6, 16) = %v, want [6 8 12]", got)
+	}
+
+	empty := newList()
+	it = empty.Iterator()
+	if it.SeekToLast(); it.Valid() {
+		t.Error("SeekToLast() on empty list is valid")
+	}
+}
+
+func testNearest(t *testing.T, newList func() skiplist.SkipList) {
+	sl := newList()
+	for i := 1; i <= 10; i++ {
+		sl.Put(skiplist.K(i*10), skiplist.V(i))
+	}
+	sl.Delete(50)
+
+	tests := []struct {
+		name string
+		fn   func(skiplist.K) (skiplist.K, skiplist.V, bool)
+		key  skiplist.K
+		want skiplist.K
+		ok   bool
+	}{
+		{"Floor", sl.Floor, 30, 30, true},
+		{"Floor", sl.Floor, 55, 40, true},
+		{"Floor", sl.Floor, 5, 0, false},
+		{"Ceiling", sl.Ceiling, 30, 30, true},
+		{"Ceiling", sl.Ceiling, 45, 60, true},
+		{"Ceiling", sl.Ceiling, 101, 0, false},
+		{"Predecessor", sl.Predecessor, 30, 20, true},
+		{"Predecessor", sl.Predecessor, 60, 40, true},
+		{"Predecessor", sl.Predecessor, 10, 0, false},
+		{"Successor", sl.Successor, 30, 40, true},
+		{"Successor", sl.Successor, 40, 60, true},
+		{"Successor", sl.Successor, 100, 0, false},
+	}
+	for _, tt := range tests {
+		k, v, ok := tt.fn(tt.key)
+		if ok != tt.ok || (ok && (k != tt.want || v != skiplist.V(k/10))) {
+			t.Errorf("%s(%d) = (%d, %f, %v), want (%d, %v)", tt.name, tt.key, k, v, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func testPutDeleteResults(t *testing.T, newList func() skiplist.SkipList) {
+	sl := newList()
+	for i := 0; i < 50; i++ {
+		if old, replaced := sl.Put(skiplist.K(i), skiplist.V(i)); replaced {
+			t.Fatalf("Put(%d) on new key = (%f, true), want replaced = false", i, old)
+		}
+	}
+	if old, replaced := sl.Put(7, 70); !replaced || old != 7 {
+		t.Errorf("Put(7) = (%f, %v), want (7, true)", old, replaced)
+	}
+	if sl.Len() != 50 {
+		t.Errorf("Len() = %d after updates, want 50", sl.Len())
+	}
+
+	if v, found := sl.Delete(7); !found || v != 70 {
+		t.Errorf("Delete(7) = (%f, %v), want (70, true)", v, found)
+	}
+	if _, found := sl.Delete(7); found {
+		t.Error("second Delete(7) found = true, want false")
+	}
+	if _, found := sl.Delete(100); found {
+		t.Error("Delete(100) on absent key found = true, want false")
+	}
+	if sl.Len() != 49 {
+		t.Errorf("Len() = %d after deletes, want 49", sl.Len())
+	}
+
+	if _, replaced := sl.Put(7, 7); replaced {
+		t.Error("Put(7) after delete replaced = true, want false")
+	}
+	if sl.Len() != 50 {
+		t.Errorf("Len() = %d after re-insert, want 50", sl.Len())
+	}
+}
+
+func testPriorityQueue(t *testing.T, newList func() skiplist.SkipList) {
+	sl := newList()
+	if _, _, ok := sl.PopMin(); ok {
+		t.Error("PopMin() on empty list ok = true, want false")
+	}
+	for _, k := range []skiplist.K{5, 1, 9, 3, 7, 2, 8, 4, 6} {
+		sl.Put(k, skiplist.V(k*10))
+	}
+	sl.Delete(1)
+	sl.Delete(9)
+	if k, v, ok := sl.Min(); !ok || k != 2 || v != 20 {
+		t.Errorf("Min() = (%d, %f, %v), want (2, 20, true)", k, v, ok)
+	}
+	if k, v, ok := sl.Max(); !ok || k != 8 || v != 80 {
+		t.Errorf("Max() = (%d, %f, %v), want (8, 80, true)", k, v, ok)
+	}
+
+	lo, hi := skiplist.K(2), skiplist.K(8)
+	for sl.Len() > 0 {
+		k, _, ok := sl.PopMin()
+		if !ok || k != lo {
+			t.Fatalf("PopMin() = (%d, %v), want (%d, true)", k, ok, lo)
+		}
+		lo++
+		if sl.Len() == 0 {
+			break
+		}
+		k, _, ok = sl.PopMax()
+		if !ok || k != hi {
+			t.Fatalf("PopMax() = (%d, %v), want (%d, true)", k, ok, hi)
+		}
+		hi--
+	}
+	if lo != hi+1 {
+		t.Errorf("popped up to %d from the front and %d from the back, want them to meet", lo, hi)
+	}
+	if _, _, ok := sl.Max(); ok {
+		t.Error("Max() on drained list ok = true, want false")
+	}
+}
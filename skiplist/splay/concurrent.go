@@ -0,0 +1,559 @@
+package splay
+
+import (
+	"cmp"
+	"iter"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+)
+
+// cEntry 是不可變的 (value, 刪除標記) 組合，整個替換讓更新值與邏輯刪除不會互相覆蓋
+type cEntry[V any] struct {
+	value   V
+	deleted bool
+	removed bool // 節點已被 PopMin/PopMax 實體移除，不可再寫入，需插入新節點
+}
+
+type cNode[K, V any] struct {
+	key       K
+	entry     atomic.Pointer[cEntry[V]]
+	zeroLevel int32 // 以下欄位只在持有 list.mu 時修改
+	topLevel  int32
+	selfhits  int32
+	hits      [MAX_LEVEL + 1]int32
+	next      [MAX_LEVEL + 1]atomic.Pointer[cNode[K, V]]
+}
+
+// ConcurrentSplayList 論文中的併發版 Splay-List 的簡化版本。
+// 讀取不取鎖，只以 atomic 讀取 next；結構調整（插入與 update 的 ascend/descend）
+// 以單一的全域鎖 mu 串行化，而非論文中只鎖住每次局部旋轉涉及的節點，
+// 因此多個 goroutine 同時插入或調整時的擴展性受限於 mu 的競爭；
+// 每次存取只以機率 p 進入 update，降低鎖的競爭。
+// zero level 下移時會先把所有節點延伸到新的一層再公布，讀取途中若發現 zero level
+// 改變就重試，因此讀取永遠不會寫入節點。
+type ConcurrentSplayList[K, V any] struct {
+	mu        sync.Mutex
+	m         int32 // 受 mu 保護
+	zeroLevel atomic.Int32
+	head      *cNode[K, V]
+	p         float64
+	size      atomic.Int32
+	compare   skiplist.Comparator[K]
+}
+
+func NewConcurrentSplayList(p float64) *ConcurrentSplayList[skiplist.K, skiplist.V] {
+	return NewConcurrentSplayListOf[skiplist.K, skiplist.V](p)
+}
+
+// NewConcurrentSplayListOf 建立任意可排序 key 的 ConcurrentSplayList
+func NewConcurrentSplayListOf[K cmp.Ordered, V any](p float64) *ConcurrentSplayList[K, V] {
+	return NewConcurrentSplayListFunc[K, V](p, cmp.Compare[K])
+}
+
+// NewConcurrentSplayListFunc 建立以 compare 決定 key 順序的 ConcurrentSplayList
+func NewConcurrentSplayListFunc[K, V any](p float64, compare func(a, b K) int) *ConcurrentSplayList[K, V] {
+	list := &ConcurrentSplayList[K, V]{
+		head:    &cNode[K, V]{topLevel: MAX_LEVEL, zeroLevel: MAX_LEVEL - 1},
+		p:       p,
+		compare: compare,
+	}
+	list.zeroLevel.Store(MAX_LEVEL - 1)
+	return list
+}
+
+// find 無鎖搜尋 key
+func (list *ConcurrentSplayList[K, V]) find(key K) *cNode[K, V] {
+	for {
+		z := list.zeroLevel.Load()
+		pred := list.head
+		for level := int32(MAX_LEVEL - 1); level >= z; level-- {
+			succ := pred.next[level].Load()
+			for succ != nil && list.compare(succ.key, key) < 0 {
+				pred = succ
+				succ = pred.next[level].Load()
+			}
+			if succ != nil && list.compare(succ.key, key) == 0 {
+				return succ
+			}
+		}
+		// 搜尋途中 zero level 下移，舊的最底層可能已缺少節點
+		if list.zeroLevel.Load() == z {
+			return nil
+		}
+	}
+}
+
+func (list *ConcurrentSplayList[K, V]) tryUpdate(key K) {
+	if rand.Float64() > list.p {
+		return
+	}
+	list.mu.Lock()
+	list.update(key)
+	list.mu.Unlock()
+}
+
+func (list *ConcurrentSplayList[K, V]) Contains(key K) bool {
+	node := list.find(key)
+	if node == nil {
+		return false
+	}
+	list.tryUpdate(key)
+	return !node.entry.Load().deleted
+}
+
+func (list *ConcurrentSplayList[K, V]) Get(key K) (V, bool) {
+	var zero V
+	node := list.find(key)
+	if node == nil {
+		return zero, false
+	}
+	list.tryUpdate(key)
+	e := node.entry.Load()
+	if e.deleted {
+		return zero, false
+	}
+	return e.value, true
+}
+
+// store 更新既有節點的值，節點已被標記刪除時視為重新插入；
+// 節點已被實體移除時 ok 為 false，需改為插入新節點
+func (list *ConcurrentSplayList[K, V]) store(node *cNode[K, V], value V) (old V, replaced, ok bool) {
+	var zero V
+	next := &cEntry[V]{value: value}
+	for {
+		e := node.entry.Load()
+		if e.removed {
+			return zero, false, false
+		}
+		if node.entry.CompareAndSwap(e, next) {
+			if e.deleted {
+				list.size.Add(1)
+				return zero, false, true
+			}
+			return e.value, true, true
+		}
+	}
+}
+
+func (list *ConcurrentSplayList[K, V]) Put(key K, value V) (V, bool) {
+	if node := list.find(key); node != nil {
+		if old, replaced, ok := list.store(node, value); ok {
+			list.tryUpdate(key)
+			return old, replaced
+		}
+	}
+
+	list.mu.Lock()
+	defer list.mu.Unlock()
+	if node := list.find(key); node != nil {
+		// 等待鎖的期間已被其他 goroutine 插入；持有 mu 時找到的節點不會被移除
+		old, replaced, _ := list.store(node, value)
+		list.update(key)
+		return old, replaced
+	}
+	list.insertNewNode(key, value)
+	list.size.Add(1)
+	list.update(key) //必定更新
+	var zero V
+	return zero, false
+}
+
+func (list *ConcurrentSplayList[K, V]) Delete(key K) (V, bool) {
+	var zero V
+	node := list.find(key)
+	if node == nil {
+		return zero, false
+	}
+	list.tryUpdate(key)
+	for {
+		e := node.entry.Load()
+		if e.deleted {
+			return zero, false
+		}
+		if node.entry.CompareAndSwap(e, &cEntry[V]{value: e.value, deleted: true}) {
+			list.size.Add(-1)
+			return e.value, true
+		}
+	}
+}
+
+func (list *ConcurrentSplayList[K, V]) Len() int {
+	return int(list.size.Load())
+}
+
+// insertNewNode 在 zero level 插入新節點，呼叫者需持有 mu
+func (list *ConcurrentSplayList[K, V]) insertNewNode(key K, value V) {
+	z := list.zeroLevel.Load()
+	newNode := &cNode[K, V]{
+		key:       key,
+		topLevel:  z,
+		zeroLevel: z,
+		selfhits:  1, // 新節點的 hit 為 1
+	}
+	newNode.entry.Store(&cEntry[V]{value: value})
+
+	pred := list.head
+	for h := int32(MAX_LEVEL); h >= z; h-- {
+		curr := pred.next[h].Load()
+		for curr != nil && list.compare(curr.key, key) < 0 {
+			pred = curr
+			curr = pred.next[h].Load()
+		}
+		if h == z {
+			// 先設定新節點的 next 再公布，讀取者不會看到未完成的節點
+			newNode.next[h].Store(curr)
+			pred.next[h].Store(newNode)
+		}
+	}
+}
+
+// lowerZeroLevel 將所有節點延伸到下一層後才公布新的 zero level，呼叫者需持有 mu
+func (list *ConcurrentSplayList[K, V]) lowerZeroLevel() {
+	z := list.zeroLevel.Load()
+	for node := list.head; node != nil; node = node.next[z].Load() {
+		node.hits[z-1] = 0
+		node.next[z-1].Store(node.next[z].Load())
+		node.zeroLevel = z - 1
+	}
+	list.zeroLevel.Store(z - 1)
+}
+
+// hitsAt 節點在第 h 層（含自身）的 hits
+func (n *cNode[K, V]) hitsAt(h int32) int32 {
+	return n.selfhits + n.hits[h]
+}
+
+// update 與 SplayList.update 相同的 balancing phase，呼叫者需持有 mu。
+// 所有節點都已延伸到 zero level，因此不需要 updateUpToLevel。
+func (list *ConcurrentSplayList[K, V]) update(key K) {
+	list.m++
+	m := list.m
+
+	pred := list.head
+	pred.hits[MAX_LEVEL]++
+	var prepred, curr *cNode[K, V]
+	for level := int32(MAX_LEVEL - 1); level >= list.zeroLevel.Load(); level-- {
+		prepred = pred
+		curr = pred.next[level].Load()
+		if curr == nil || list.compare(curr.key, key) > 0 { //走一步就過頭
+			pred.hits[level]++
+			continue
+		}
+
+		found := false
+		for curr != nil && list.compare(curr.key, key) <= 0 {
+			next := curr.next[level].Load()
+			if next == nil || list.compare(next.key, key) > 0 {
+				if list.compare(curr.key, key) == 0 {
+					found = true
+					curr.selfhits++
+				} else {
+					curr.hits[level]++
+				}
+				break
+			}
+
+			//ascent condition
+			curh := curr.topLevel
+			if curh+1 < MAX_LEVEL && curh < prepred.topLevel && prepred.hits[curh+1]-prepred.hits[curh] > list.getAscentThreshold(curh, m) {
+				for curh+1 < MAX_LEVEL && curh < prepred.topLevel && prepred.hits[curh+1]-prepred.hits[curh] > list.getAscentThreshold(curh, m) {
+					curr.topLevel++
+					curh++
+					curr.hits[curh] = prepred.hits[curh] - prepred.hits[curh-1] - curr.selfhits
+					curr.next[curh].Store(prepred.next[curh].Load())
+					prepred.hits[curh] = prepred.hits[curh-1]
+					prepred.next[curh].Store(curr)
+				}
+				prepred = curr
+				pred = curr
+				curr = pred.next[level].Load()
+				continue // 升級後無需判定降級
+
+				//descend condition
+			} else if curr.topLevel == level && level > 0 &&
+				curr.hitsAt(level)+pred.hitsAt(level) <= list.getDescentThreshold(level, m) {
+				if level == list.zeroLevel.Load() {
+					//擴張
+					list.lowerZeroLevel()
+				}
+				pred.hits[level] += curr.hitsAt(level)
+				curr.hits[level] = 0
+				pred.next[level].Store(next)
+				curr.next[level].Store(nil)
+				curr.topLevel--
+				curr = next
+				continue
+			}
+			//沒上升也沒下降
+			pred = curr
+			curr = next
+		}
+		if found {
+			return
+		}
+	}
+}
+
+func (list *ConcurrentSplayList[K, V]) getAscentThreshold(h int32, M int32) int32 {
+	return M / (1 << (MAX_LEVEL - 1 - h))
+}
+
+func (list *ConcurrentSplayList[K, V]) getDescentThreshold(h int32, M int32) int32 {
+	return M / (1 << (MAX_LEVEL - h))
+}
+
+// GetHead 節點的層級資訊只有在沒有其他 goroutine 寫入時才一致，僅供分析工具使用
+func (list *ConcurrentSplayList[K, V]) GetHead() skiplist.NodelikeOf[K, V] {
+	return list.head
+}
+
+func (list *ConcurrentSplayList[K, V]) GetMaxStats() (maxNodes int, maxLevel int) {
+	return list.Len(), int(MAX_LEVEL - list.zeroLevel.Load())
+}
+
+func (list *ConcurrentSplayList[K, V]) Compare(a, b K) int {
+	return list.compare(a, b)
+}
+
+func (n *cNode[K, V]) GetKey() K {
+	return n.key
+}
+
+func (n *cNode[K, V]) GetValue() V {
+	var zero V
+	if e := n.entry.Load(); e != nil {
+		return e.value
+	}
+	return zero
+}
+
+func (n *cNode[K, V]) GetLevel() int32 {
+	return n.topLevel - n.zeroLevel
+}
+
+func (n *cNode[K, V]) GetNextAt(level int32) skiplist.NodelikeOf[K, V] {
+	trueLevel := level + n.zeroLevel
+	if trueLevel < 0 || trueLevel > n.topLevel {
+		return nil
+	}
+	next := n.next[trueLevel].Load()
+	if next == nil {
+		return nil
+	}
+	return next
+}
+
+// cIterator 是弱一致的迭代器，只讀取不觸發 balancing
+type cIterator[K, V any] struct {
+	list  *ConcurrentSplayList[K, V]
+	cur   *cNode[K, V]
+	entry *cEntry[V] // 移到 cur 時讀到的值
+}
+
+// findLess 回傳最後一個 key 小於指定 key 的節點（含已刪除），沒有時回傳 head
+func (list *ConcurrentSplayList[K, V]) findLess(key K) *cNode[K, V] {
+	for {
+		z := list.zeroLevel.Load()
+		pred := list.head
+		for level := int32(MAX_LEVEL - 1); level >= z; level-- {
+			for succ := pred.next[level].Load(); succ != nil && list.compare(succ.key, key) < 0; succ = pred.next[level].Load() {
+				pred = succ
+			}
+		}
+		if list.zeroLevel.Load() == z {
+			return pred
+		}
+	}
+}
+
+// findLast 回傳最後一個節點（含已刪除），串列為空時回傳 head
+func (list *ConcurrentSplayList[K, V]) findLast() *cNode[K, V] {
+	for {
+		z := list.zeroLevel.Load()
+		pred := list.head
+		for level := int32(MAX_LEVEL - 1); level >= z; level-- {
+			for succ := pred.next[level].Load(); succ != nil; succ = pred.next[level].Load() {
+				pred = succ
+			}
+		}
+		if list.zeroLevel.Load() == z {
+			return pred
+		}
+	}
+}
+
+// nextAt0 讀取 node 在目前最底層的後繼
+func (list *ConcurrentSplayList[K, V]) nextAt0(node *cNode[K, V]) *cNode[K, V] {
+	for {
+		z := list.zeroLevel.Load()
+		next := node.next[z].Load()
+		if list.zeroLevel.Load() == z {
+			return next
+		}
+	}
+}
+
+// seekAlive 由 node 往後找第一個未刪除的節點，node 本身也列入考慮
+func (it *cIterator[K, V]) seekAlive(node *cNode[K, V]) {
+	for ; node != nil; node = it.list.nextAt0(node) {
+		if e := node.entry.Load(); !e.deleted {
+			it.cur, it.entry = node, e
+			return
+		}
+	}
+	it.cur, it.entry = nil, nil
+}
+
+// seekAliveBefore 由 node 往前找第一個未刪除的節點，node 本身也列入考慮
+func (it *cIterator[K, V]) seekAliveBefore(node *cNode[K, V]) {
+	for node != it.list.head {
+		if e := node.entry.Load(); !e.deleted {
+			it.cur, it.entry = node, e
+			return
+		}
+		node = it.list.findLess(node.key)
+	}
+	it.cur, it.entry = nil, nil
+}
+
+func (list *ConcurrentSplayList[K, V]) Iterator() skiplist.IteratorOf[K, V] {
+	return &cIterator[K, V]{list: list}
+}
+
+func (list *ConcurrentSplayList[K, V]) Range(lo, hi K, fn func(key K, value V) bool) {
+	skiplist.RangeOf(list.Iterator(), list.compare, lo, hi, fn)
+}
+
+func (list *ConcurrentSplayList[K, V]) All() iter.Seq2[K, V] {
+	return skiplist.AllOf(list.Iterator)
+}
+
+func (list *ConcurrentSplayList[K, V]) Backward() iter.Seq2[K, V] {
+	return skiplist.BackwardOf(list.Iterator)
+}
+
+// recordAccess 將查詢回傳的元素視為一次存取
+func (list *ConcurrentSplayList[K, V]) recordAccess(k K, v V, ok bool) (K, V, bool) {
+	if ok {
+		list.tryUpdate(k)
+	}
+	return k, v, ok
+}
+
+func (list *ConcurrentSplayList[K, V]) Floor(key K) (K, V, bool) {
+	return list.recordAccess(skiplist.FloorOf(list.Iterator(), list.compare, key))
+}
+
+func (list *ConcurrentSplayList[K, V]) Ceiling(key K) (K, V, bool) {
+	return list.recordAccess(skiplist.CeilingOf(list.Iterator(), key))
+}
+
+func (list *ConcurrentSplayList[K, V]) Predecessor(key K) (K, V, bool) {
+	return list.recordAccess(skiplist.PredecessorOf(list.Iterator(), key))
+}
+
+func (list *ConcurrentSplayList[K, V]) Successor(key K) (K, V, bool) {
+	return list.recordAccess(skiplist.SuccessorOf(list.Iterator(), list.compare, key))
+}
+
+func (list *ConcurrentSplayList[K, V]) Min() (K, V, bool) {
+	return skiplist.MinOf(list.Iterator())
+}
+
+func (list *ConcurrentSplayList[K, V]) Max() (K, V, bool) {
+	return skiplist.MaxOf(list.Iterator())
+}
+
+// PopMin 在 mu 內取出最小元素並實體移除，途經的已刪除節點一併移除；
+// 多個消費者不會取得同一個元素
+func (list *ConcurrentSplayList[K, V]) PopMin() (K, V, bool) {
+	list.mu.Lock()
+	defer list.mu.Unlock()
+	for {
+		node := list.head.next[list.zeroLevel.Load()].Load()
+		if node == nil {
+			break
+		}
+		if e := list.remove(node); !e.deleted {
+			return node.key, e.value, true
+		}
+	}
+	var k K
+	var v V
+	return k, v, false
+}
+
+// PopMax 在 mu 內取出最大元素並實體移除，途經的已刪除節點一併移除
+func (list *ConcurrentSplayList[K, V]) PopMax() (K, V, bool) {
+	list.mu.Lock()
+	defer list.mu.Unlock()
+	for node := list.findLast(); node != list.head; node = list.findLast() {
+		if e := list.remove(node); !e.deleted {
+			return node.key, e.value, true
+		}
+	}
+	var k K
+	var v V
+	return k, v, false
+}
+
+// remove 將 node 標記為已移除後實體移除，回傳標記前的值；呼叫者需持有 mu
+func (list *ConcurrentSplayList[K, V]) remove(node *cNode[K, V]) *cEntry[V] {
+	e := node.entry.Swap(&cEntry[V]{deleted: true, removed: true})
+	if !e.deleted {
+		list.size.Add(-1)
+	}
+	list.unlink(node)
+	return e
+}
+
+// unlink 將 node 自所有層實體移除，hits 與 descent 相同併入各層的前驅，呼叫者需持有 mu。
+// node 的 next 保持不變並補齊到第 0 層，停在 node 的讀取者在 zero level 下移後仍可前進
+func (list *ConcurrentSplayList[K, V]) unlink(node *cNode[K, V]) {
+	z := list.zeroLevel.Load()
+	pred := list.head
+	for h := int32(MAX_LEVEL - 1); h >= z; h-- {
+		for succ := pred.next[h].Load(); succ != nil && list.compare(succ.key, node.key) < 0; succ = pred.next[h].Load() {
+			pred = succ
+		}
+		if pred.next[h].Load() == node {
+			pred.hits[h] += node.hitsAt(h)
+			pred.next[h].Store(node.next[h].Load())
+		}
+	}
+	next := node.next[z].Load()
+	for h := int32(0); h < z; h++ {
+		node.next[h].Store(next)
+	}
+}
+
+func (it *cIterator[K, V]) Valid() bool { return it.cur != nil }
+func (it *cIterator[K, V]) Key() K      { return it.cur.key }
+func (it *cIterator[K, V]) Value() V    { return it.entry.value }
+
+func (it *cIterator[K, V]) Next() {
+	it.seekAlive(it.list.nextAt0(it.cur))
+}
+
+func (it *cIterator[K, V]) Prev() {
+	it.SeekBefore(it.cur.key)
+}
+
+func (it *cIterator[K, V]) SeekBefore(key K) {
+	it.seekAliveBefore(it.list.findLess(key))
+}
+
+func (it *cIterator[K, V]) Seek(key K) {
+	it.seekAlive(it.list.nextAt0(it.list.findLess(key)))
+}
+
+func (it *cIterator[K, V]) SeekToFirst() {
+	it.seekAlive(it.list.nextAt0(it.list.head))
+}
+
+func (it *cIterator[K, V]) SeekToLast() {
+	it.seekAliveBefore(it.list.findLast())
+}
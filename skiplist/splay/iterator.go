@@ -0,0 +1,169 @@
+package splay
+
+import (
+	"iter"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+)
+
+// splayIterator 走訪時只補齊 zeroLevel 的延遲連結，不記錄 hits，也不觸發 balancing
+type splayIterator[K, V any] struct {
+	list *SplayList[K, V]
+	cur  *SplayNode[K, V]
+}
+
+// findLess 回傳最後一個 key 小於指定 key 的節點（含已刪除），沒有時回傳 head
+func (list *SplayList[K, V]) findLess(key K) *SplayNode[K, V] {
+	pred := list.head
+	for level := int32(MAX_LEVEL - 1); level >= list.zeroLevel; level-- {
+		list.updateUpToLevel(pred, level)
+		for succ := pred.next[level]; succ != nil && list.compare(succ.key, key) < 0; succ = pred.next[level] {
+			list.updateUpToLevel(succ, level)
+			pred = succ
+		}
+	}
+	return pred
+}
+
+// findLast 回傳最後一個節點（含已刪除），串列為空時回傳 head
+func (list *SplayList[K, V]) findLast() *SplayNode[K, V] {
+	pred := list.head
+	for level := int32(MAX_LEVEL - 1); level >= list.zeroLevel; level-- {
+		list.updateUpToLevel(pred, level)
+		for succ := pred.next[level]; succ != nil; succ = pred.next[level] {
+			list.updateUpToLevel(succ, level)
+			pred = succ
+		}
+	}
+	return pred
+}
+
+// nextAlive 回傳 node 之後第一個未刪除的節點
+func (list *SplayList[K, V]) nextAlive(node *SplayNode[K, V]) *SplayNode[K, V] {
+	for {
+		list.updateUpToLevel(node, list.zeroLevel)
+		node = node.next[list.zeroLevel]
+		if node == nil || !node.deleted {
+			return node
+		}
+	}
+}
+
+// prevAlive 由 node 往前找第一個未刪除的節點，node 本身也列入考慮
+func (list *SplayList[K, V]) prevAlive(node *SplayNode[K, V]) *SplayNode[K, V] {
+	for node != list.head && node.deleted {
+		node = list.findLess(node.key)
+	}
+	if node == list.head {
+		return nil
+	}
+	return node
+}
+
+func (list *SplayList[K, V]) Iterator() skiplist.IteratorOf[K, V] {
+	return &splayIterator[K, V]{list: list}
+}
+
+func (list *SplayList[K, V]) Range(lo, hi K, fn func(key K, value V) bool) {
+	skiplist.RangeOf(list.Iterator(), list.compare, lo, hi, fn)
+}
+
+func (list *SplayList[K, V]) All() iter.Seq2[K, V] {
+	return skiplist.AllOf(list.Iterator)
+}
+
+func (list *SplayList[K, V]) Backward() iter.Seq2[K, V] {
+	return skiplist.BackwardOf(list.Iterator)
+}
+
+// recordAccess 將查詢回傳的元素視為一次存取，以 tryUpdate 計入 hits
+func (list *SplayList[K, V]) recordAccess(k K, v V, ok bool) (K, V, bool) {
+	if ok {
+		list.tryUpdate(k)
+	}
+	return k, v, ok
+}
+
+func (list *SplayList[K, V]) Floor(key K) (K, V, bool) {
+	return list.recordAccess(skiplist.FloorOf(list.Iterator(), list.compare, key))
+}
+
+func (list *SplayList[K, V]) Ceiling(key K) (K, V, bool) {
+	return list.recordAccess(skiplist.CeilingOf(list.Iterator(), key))
+}
+
+func (list *SplayList[K, V]) Predecessor(key K) (K, V, bool) {
+	return list.recordAccess(skiplist.PredecessorOf(list.Iterator(), key))
+}
+
+func (list *SplayList[K, V]) Successor(key K) (K, V, bool) {
+	return list.recordAccess(skiplist.SuccessorOf(list.Iterator(), list.compare, key))
+}
+
+func (list *SplayList[K, V]) Min() (K, V, bool) {
+	return skiplist.MinOf(list.Iterator())
+}
+
+func (list *SplayList[K, V]) Max() (K, V, bool) {
+	return skiplist.MaxOf(list.Iterator())
+}
+
+// PopMin 取出最小元素並將其實體移除，途經的已刪除節點一併移除，
+// 連續取出時不會反覆走過墓碑
+func (list *SplayList[K, V]) PopMin() (K, V, bool) {
+	for {
+		list.updateUpToLevel(list.head, list.zeroLevel)
+		node := list.head.next[list.zeroLevel]
+		if node == nil {
+			break
+		}
+		list.unlink(node)
+		if !node.deleted {
+			return list.popped(node)
+		}
+	}
+	var k K
+	var v V
+	return k, v, false
+}
+
+// PopMax 取出最大元素並將其實體移除，途經的已刪除節點一併移除
+func (list *SplayList[K, V]) PopMax() (K, V, bool) {
+	for node := list.findLast(); node != list.head; node = list.findLast() {
+		list.unlink(node)
+		if !node.deleted {
+			return list.popped(node)
+		}
+	}
+	var k K
+	var v V
+	return k, v, false
+}
+
+func (it *splayIterator[K, V]) Valid() bool { return it.cur != nil }
+func (it *splayIterator[K, V]) Key() K      { return it.cur.key }
+func (it *splayIterator[K, V]) Value() V    { return it.cur.value }
+
+func (it *splayIterator[K, V]) Next() {
+	it.cur = it.list.nextAlive(it.cur)
+}
+
+func (it *splayIterator[K, V]) Prev() {
+	it.SeekBefore(it.cur.key)
+}
+
+func (it *splayIterator[K, V]) SeekBefore(key K) {
+	it.cur = it.list.prevAlive(it.list.findLess(key))
+}
+
+func (it *splayIterator[K, V]) Seek(key K) {
+	it.cur = it.list.nextAlive(it.list.findLess(key))
+}
+
+func (it *splayIterator[K, V]) SeekToFirst() {
+	it.cur = it.list.nextAlive(it.list.head)
+}
+
+func (it *splayIterator[K, V]) SeekToLast() {
+	it.cur = it.list.prevAlive(it.list.findLast())
+}
@@ -0,0 +1,34 @@
+package splay
+
+import (
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/registry"
+)
+
+func init() {
+	registry.Register(registry.Impl{
+		Name:   "splay",
+		Usage:  "依存取次數調整高度的 splay list",
+		Params: []registry.Param{{Name: "p", Kind: registry.Float, Default: "0.01", Usage: "查詢時進行調整的機率"}},
+		New: func(p registry.Params, env registry.Env) (skiplist.SkipList, error) {
+			prob, err := p.Probability("p")
+			if err != nil {
+				return nil, err
+			}
+			return NewSplayList(prob), nil
+		},
+	})
+	registry.Register(registry.Impl{
+		Name:       "splay-concurrent",
+		Usage:      "讀取不加鎖的 splay list",
+		ThreadSafe: true,
+		Params:     []registry.Param{{Name: "p", Kind: registry.Float, Default: "0.01", Usage: "查詢時進行調整的機率"}},
+		New: func(p registry.Params, env registry.Env) (skiplist.SkipList, error) {
+			prob, err := p.Probability("p")
+			if err != nil {
+				return nil, err
+			}
+			return NewConcurrentSplayList(prob), nil
+		},
+	})
+}
@@ -1,6 +1,7 @@
 package splay
 
 import (
+	"cmp"
 	"math/rand"
 
 	// "sync"
@@ -10,39 +11,52 @@ import (
 
 const MAX_LEVEL = 32 // 根據實際需求調整
 
-type SplayNode struct {
-	key       skiplist.K
-	value     skiplist.V
+type SplayNode[K, V any] struct {
+	key       K
+	value     V
 	zeroLevel int32
 	topLevel  int32
 	selfhits  int32
-	next      [MAX_LEVEL + 1]*SplayNode
+	next      [MAX_LEVEL + 1]*SplayNode[K, V]
 	hits      [MAX_LEVEL + 1]int32
 	deleted   bool
 }
 
-type SplayList struct {
-	m         int32      // 動態計數器，記錄目前操作次數，供 balancing phase 使用
-	zeroLevel int32      // 記錄當前 zero level
-	head      *SplayNode // 頭節點
-	p         float64    // 平衡條件的常數，初始化後不變
-	size      int32      // 記錄當前節點數
+type SplayList[K, V any] struct {
+	m         int32                  // 動態計數器，記錄目前操作次數，供 balancing phase 使用
+	zeroLevel int32                  // 記錄當前 zero level
+	head      *SplayNode[K, V]       // 頭節點
+	p         float64                // 平衡條件的常數，初始化後不變
+	size      int32                  // 記錄當前節點數
+	compare   skiplist.Comparator[K] // key 的比較函式
 }
 
-func NewSplayList(p float64) *SplayList {
-	head := &SplayNode{topLevel: MAX_LEVEL, zeroLevel: MAX_LEVEL - 1}
+func NewSplayList(p float64) *SplayList[skiplist.K, skiplist.V] {
+	return NewSplayListOf[skiplist.K, skiplist.V](p)
+}
+
+// NewSplayListOf 建立任意可排序 key 的 SplayList
+func NewSplayListOf[K cmp.Ordered, V any](p float64) *SplayList[K, V] {
+	return NewSplayListFunc[K, V](p, cmp.Compare[K])
+}
+
+// NewSplayListFunc 建立以 compare 決定 key 順序的 SplayList，
+// 可用於 []byte 或複合 key 等無法直接以 < 比較的型別
+func NewSplayListFunc[K, V any](p float64, compare func(a, b K) int) *SplayList[K, V] {
+	head := &SplayNode[K, V]{topLevel: MAX_LEVEL, zeroLevel: MAX_LEVEL - 1}
 	for i := 0; i <= MAX_LEVEL; i++ {
 		head.next[i] = nil
 	}
-	return &SplayList{
+	return &SplayList[K, V]{
 		head:      head,
 		zeroLevel: MAX_LEVEL - 1,
 		p:         p,
+		compare:   compare,
 	}
 }
 
 // contains 函式
-func (list *SplayList) Contains(key skiplist.K) bool {
+func (list *SplayList[K, V]) Contains(key K) bool {
 	node := list.find(key)
 	if node == nil {
 		return false
@@ -52,9 +66,9 @@ func (list *SplayList) Contains(key skiplist.K) bool {
 }
 
 // find 函式
-func (list *SplayList) find(key skiplist.K) *SplayNode {
+func (list *SplayList[K, V]) find(key K) *SplayNode[K, V] {
 	pred := list.head
-	var succ *SplayNode
+	var succ *SplayNode[K, V]
 	for level := int32(MAX_LEVEL - 1); level >= list.zeroLevel; level-- {
 		list.updateUpToLevel(pred, level)
 		succ = pred.next[level]
@@ -62,7 +76,7 @@ func (list *SplayList) find(key skiplist.K) *SplayNode {
 			continue
 		}
 		list.updateUpToLevel(succ, level)
-		for succ != nil && succ.key < key {
+		for succ != nil && list.compare(succ.key, key) < 0 {
 			pred = succ
 			succ = pred.next[level]
 			if succ == nil {
@@ -70,7 +84,7 @@ func (list *SplayList) find(key skiplist.K) *SplayNode {
 			}
 			list.updateUpToLevel(succ, level)
 		}
-		if succ != nil && succ.key == key {
+		if succ != nil && list.compare(succ.key, key) == 0 {
 			return succ
 		}
 	}
@@ -78,7 +92,7 @@ func (list *SplayList) find(key skiplist.K) *SplayNode {
 }
 
 // updateUpToLevel 函式
-func (list *SplayList) updateUpToLevel(node *SplayNode, level int32) {
+func (list *SplayList[K, V]) updateUpToLevel(node *SplayNode[K, V], level int32) {
 	if node == nil {
 		return
 	}
@@ -93,7 +107,7 @@ func (list *SplayList) updateUpToLevel(node *SplayNode, level int32) {
 }
 
 // getHits 函式：計算節點在指定層的 hits
-func getHits(node *SplayNode, h int32) int32 {
+func getHits[K, V any](node *SplayNode[K, V], h int32) int32 {
 	if node.zeroLevel > h {
 		return node.selfhits
 	}
@@ -101,28 +115,28 @@ func getHits(node *SplayNode, h int32) int32 {
 }
 
 // update 函式：根據論文偽碼實作 balancing phase
-func (list *SplayList) update(key skiplist.K) {
+func (list *SplayList[K, V]) update(key K) {
 	list.m++
 
 	pred := list.head
 	pred.hits[MAX_LEVEL]++
-	var prepred, curr *SplayNode
+	var prepred, curr *SplayNode[K, V]
 	for level := int32(MAX_LEVEL - 1); level >= list.zeroLevel; level-- {
 		list.updateUpToLevel(pred, level)
 		prepred = pred
 		curr = pred.next[level]
 		list.updateUpToLevel(curr, level)
-		if curr == nil || curr.key > key { //走一步就過頭
+		if curr == nil || list.compare(curr.key, key) > 0 { //走一步就過頭
 			pred.hits[level]++
 			continue
 		}
 
 		found := false
-		for curr != nil && curr.key <= key {
+		for curr != nil && list.compare(curr.key, key) <= 0 {
 			list.updateUpToLevel(curr, level)
 
-			if curr.next[level] == nil || curr.next[level].key > key {
-				if curr.key == key {
+			if curr.next[level] == nil || list.compare(curr.next[level].key, key) > 0 {
+				if list.compare(curr.key, key) == 0 {
 					found = true
 					curr.selfhits++
 				} else {
@@ -148,7 +162,7 @@ func (list *SplayList) update(key skiplist.K) {
 				continue // 升級後無需判定降級
 
 				//descend condition
-			} else if curr.topLevel == level && curr.next[level] != nil && curr.next[level].key <= key &&
+			} else if curr.topLevel == level && curr.next[level] != nil && list.compare(curr.next[level].key, key) <= 0 &&
 				getHits(curr, level)+getHits(pred, level) <= list.getDescentThreshold(level, list.m) {
 				currZero := list.zeroLevel
 				if level == currZero {
@@ -178,67 +192,104 @@ func (list *SplayList) update(key skiplist.K) {
 	// 調試用：若需要驗證 hits 可手動呼叫 CheckHits()
 }
 
-func (list *SplayList) getAscentThreshold(h int32, M int32) int32 {
+func (list *SplayList[K, V]) getAscentThreshold(h int32, M int32) int32 {
 	return M / (1 << (MAX_LEVEL - 1 - h))
 }
 
-func (list *SplayList) getDescentThreshold(h int32, M int32) int32 {
+func (list *SplayList[K, V]) getDescentThreshold(h int32, M int32) int32 {
 	return M / (1 << (MAX_LEVEL - h))
 }
 
 // Put 方法：插入或更新節點
-func (list *SplayList) Put(key skiplist.K, value skiplist.V) {
+func (list *SplayList[K, V]) Put(key K, value V) (V, bool) {
+	var zero V
 	// 先嘗試找到現有的節點
 	node := list.find(key)
 
 	if node != nil {
-		// 找到節點，檢查是否被標記為已刪除
+		// 找到節點，被標記為已刪除的節點視為重新插入
+		old, replaced := node.value, !node.deleted
 		if node.deleted {
+			old = zero
 			list.size++
 		}
 
 		node.deleted = false
 		node.value = value
 		list.tryUpdate(key)
-	} else {
-		// 沒有找到節點，建立新節點
-		list.insertNewNode(key, value)
-		list.update(key) //必定更新
-		list.size++
+		return old, replaced
 	}
-
+	// 沒有找到節點，建立新節點
+	list.insertNewNode(key, value)
+	list.update(key) //必定更新
+	list.size++
+	return zero, false
 }
 
 // Delete 方法：標記刪除節點
-func (list *SplayList) Delete(key skiplist.K) {
+func (list *SplayList[K, V]) Delete(key K) (V, bool) {
+	var zero V
 	node := list.find(key)
-	if node != nil {
-		node.deleted = true
-		list.tryUpdate(key)
-		list.size--
+	if node == nil {
+		return zero, false
+	}
+	list.tryUpdate(key)
+	if node.deleted {
+		return zero, false
+	}
+	node.deleted = true
+	list.size--
+	return node.value, true
+}
+
+// unlink 將 node 自所有層實體移除，node 的 hits 與 descent 相同併入各層的前驅；
+// node 本身的 next 保持不變，停在 node 的迭代器仍可繼續前進
+func (list *SplayList[K, V]) unlink(node *SplayNode[K, V]) {
+	pred := list.head
+	for h := int32(MAX_LEVEL - 1); h >= list.zeroLevel; h-- {
+		list.updateUpToLevel(pred, h)
+		curr := pred.next[h]
+		for curr != nil && list.compare(curr.key, node.key) < 0 {
+			list.updateUpToLevel(curr, h)
+			pred = curr
+			curr = pred.next[h]
+		}
+		if curr == node {
+			list.updateUpToLevel(node, h)
+			pred.hits[h] += getHits(node, h)
+			pred.next[h] = node.next[h]
+		}
 	}
 }
 
+// popped 將已移除的 node 標記為刪除並回傳其元素
+func (list *SplayList[K, V]) popped(node *SplayNode[K, V]) (K, V, bool) {
+	node.deleted = true
+	list.size--
+	return node.key, node.value, true
+}
+
 // Get 方法：獲取節點值
-func (list *SplayList) Get(key skiplist.K) (skiplist.V, bool) {
+func (list *SplayList[K, V]) Get(key K) (V, bool) {
+	var zero V
 	node := list.find(key)
 	if node == nil {
-		return 0, false
+		return zero, false
 	}
 	list.tryUpdate(key)
 
 	if node.deleted {
-		return 0, false
+		return zero, false
 	}
 
 	return node.value, true
 }
 
 // insertNewNode 方法：插入新節點
-func (list *SplayList) insertNewNode(key skiplist.K, value skiplist.V) {
+func (list *SplayList[K, V]) insertNewNode(key K, value V) {
 	zLevel := list.zeroLevel
 	// 建立新節點
-	newNode := &SplayNode{
+	newNode := &SplayNode[K, V]{
 		key:       key,
 		value:     value,
 		topLevel:  zLevel,
@@ -257,7 +308,7 @@ func (list *SplayList) insertNewNode(key skiplist.K, value skiplist.V) {
 }
 
 // insertNode 方法：在指定層級插入節點
-func (list *SplayList) insertNode(newNode *SplayNode, level int32) {
+func (list *SplayList[K, V]) insertNode(newNode *SplayNode[K, V], level int32) {
 	// 從最高層開始尋找插入位置
 	pred := list.head
 
@@ -268,7 +319,7 @@ func (list *SplayList) insertNode(newNode *SplayNode, level int32) {
 		// 尋找插入位置
 		curr := pred.next[h]
 		// 向右尋找插入位置
-		for curr != nil && curr.key < newNode.key {
+		for curr != nil && list.compare(curr.key, newNode.key) < 0 {
 			pred = curr
 			curr = pred.next[h]
 			if curr != nil {
@@ -284,29 +335,37 @@ func (list *SplayList) insertNode(newNode *SplayNode, level int32) {
 	}
 }
 
-func (list *SplayList) GetHead() skiplist.Nodelike {
+func (list *SplayList[K, V]) Len() int {
+	return int(list.size)
+}
+
+func (list *SplayList[K, V]) GetHead() skiplist.NodelikeOf[K, V] {
 	list.UpdateAllLvl()
 	return list.head
 }
 
-func (list *SplayList) GetMaxStats() (maxNodes int, maxLevel int) {
+func (list *SplayList[K, V]) GetMaxStats() (maxNodes int, maxLevel int) {
 	list.UpdateAllLvl()
 	return int(list.size), int(MAX_LEVEL - list.zeroLevel)
 }
 
-func (n *SplayNode) GetKey() skiplist.K {
+func (list *SplayList[K, V]) Compare(a, b K) int {
+	return list.compare(a, b)
+}
+
+func (n *SplayNode[K, V]) GetKey() K {
 	return n.key
 }
 
-func (n *SplayNode) GetValue() skiplist.V {
+func (n *SplayNode[K, V]) GetValue() V {
 	return n.value
 }
 
-func (n *SplayNode) GetLevel() int32 {
+func (n *SplayNode[K, V]) GetLevel() int32 {
 	return n.topLevel - n.zeroLevel
 }
 
-func (n *SplayNode) GetNextAt(level int32) skiplist.Nodelike {
+func (n *SplayNode[K, V]) GetNextAt(level int32) skiplist.NodelikeOf[K, V] {
 	trueLevel := level + n.zeroLevel
 	if trueLevel < 0 || trueLevel > n.topLevel {
 		return nil
@@ -317,7 +376,7 @@ func (n *SplayNode) GetNextAt(level int32) skiplist.Nodelike {
 	return n.next[trueLevel]
 }
 
-func (sl *SplayList) UpdateAllLvl() {
+func (sl *SplayList[K, V]) UpdateAllLvl() {
 	// 更新所有層級的 zeroLevel
 	h := sl.zeroLevel
 	node := sl.head
@@ -327,7 +386,7 @@ func (sl *SplayList) UpdateAllLvl() {
 	}
 }
 
-func (sl *SplayList) tryUpdate(key skiplist.K) {
+func (sl *SplayList[K, V]) tryUpdate(key K) {
 	if rand.Float64() > sl.p {
 		return
 	}
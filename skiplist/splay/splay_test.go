@@ -1,15 +1,20 @@
 package splay
 
 import (
+	"bytes"
 	"encoding/csv"
 	"fmt"
+	"math/rand"
 	"os"
+	"sync"
 	"testing"
 
 	"github.com/Hakuto4838/SkipList.git/datastream"
 	"github.com/Hakuto4838/SkipList.git/skiplist"
 	"github.com/Hakuto4838/SkipList.git/skiplist/analyTool"
 	"github.com/Hakuto4838/SkipList.git/skiplist/basic"
+	"github.com/Hakuto4838/SkipList.git/skiplist/skiplisttest"
+	"github.com/Hakuto4838/SkipList.git/skiplist/syncsl"
 )
 
 func TestSplaySkipList(t *testing.T) {
@@ -39,6 +44,53 @@ func TestSplaySkipList(t *testing.T) {
 	}
 }
 
+func TestSplayListGeneric(t *testing.T) {
+	sl := NewSplayListOf[string, int](1)
+	words := []string{"pear", "apple", "fig", "banana", "cherry"}
+	for i, w := range words {
+		sl.Put(w, i)
+	}
+	for range 100 {
+		sl.Get("fig")
+	}
+
+	for i, w := range words {
+		if value, found := sl.Get(w); !found || value != i {
+			t.Errorf("Get(%q) = (%d, %v), want (%d, true)", w, value, found, i)
+		}
+	}
+
+	sl.Delete("apple")
+	if sl.Contains("apple") {
+		t.Error("Contains(apple) = true after delete, want false")
+	}
+}
+
+func TestSplayListBytesKey(t *testing.T) {
+	sl := NewSplayListFunc[[]byte, int](1, bytes.Compare)
+	words := []string{"pear", "apple", "fig", "banana", "cherry"}
+	for i, w := range words {
+		sl.Put([]byte(w), i)
+	}
+	for range 100 {
+		sl.Get([]byte("fig"))
+	}
+
+	for i, w := range words {
+		if value, found := sl.Get([]byte(w)); !found || value != i {
+			t.Errorf("Get(%q) = (%d, %v), want (%d, true)", w, value, found, i)
+		}
+		if step, _ := analyTool.FindStepOf(sl, []byte(w)); step == 0 {
+			t.Errorf("FindStepOf(%q) = 0, want > 0", w)
+		}
+	}
+
+	sl.Delete([]byte("apple"))
+	if sl.Contains([]byte("apple")) {
+		t.Error("Contains(apple) = true after delete, want false")
+	}
+}
+
 func TestSplayListPrint(t *testing.T) {
 	sl := NewSplayList(0.5)
 	for i := 0; i < 15; i++ {
@@ -155,3 +207,359 @@ func TestSplayAndBasicSeq(t *testing.T) {
 	scoresplay, _ := analyTool.AnalyzeStep(splaySL, keymap)
 	fmt.Printf("splay score: %f\n\n", scoresplay)
 }
+
+func TestSplayListConformance(t *testing.T) {
+	skiplisttest.Run(t, func() skiplist.SkipList { return NewSplayList(0.5) })
+}
+
+func TestSplayListNearestRecordsAccess(t *testing.T) {
+	list := NewSplayList(1) // p = 1 讓每次存取都進入 balancing
+	for i := 1; i <= 10; i++ {
+		list.Put(skiplist.K(i*10), skiplist.V(i))
+	}
+	node := list.find(60)
+	before := node.selfhits
+	list.Ceiling(55)
+	list.Successor(50)
+	if node.selfhits != before+2 {
+		t.Errorf("selfhits of 60 = %d, want %d", node.selfhits, before+2)
+	}
+
+	it := list.Iterator()
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+	}
+	if node.selfhits != before+2 {
+		t.Errorf("selfhits of 60 = %d after iteration, want unchanged %d", node.selfhits, before+2)
+	}
+}
+
+// drainKeys 插入 n 個打亂的 key 並刪除其中三分之一留下墓碑，回傳存活的 key（遞增）
+func drainKeys(sl skiplist.SkipList, n int) []skiplist.K {
+	r := rand.New(rand.NewSource(11))
+	var alive []skiplist.K
+	for _, i := range r.Perm(n) {
+		sl.Put(skiplist.K(i), skiplist.V(i))
+	}
+	for i := 0; i < n; i++ {
+		if i%3 == 1 {
+			sl.Delete(skiplist.K(i))
+		} else {
+			alive = append(alive, skiplist.K(i))
+		}
+	}
+	return alive
+}
+
+// 交替 PopMin/PopMax 清空串列：順序必須正確，且取出的節點與途經的墓碑都已實體移除，
+// 下一次 Pop 不會重新走過，清空的總成本為 O(n log n)
+func TestSplayListPopDrain(t *testing.T) {
+	sl := NewSplayList(0.5)
+	alive := drainKeys(sl, 30000)
+	first := func() *SplayNode[skiplist.K, skiplist.V] {
+		sl.updateUpToLevel(sl.head, sl.zeroLevel)
+		return sl.head.next[sl.zeroLevel]
+	}
+	lo, hi := 0, len(alive)-1
+	for i := 0; lo <= hi; i++ {
+		if i%2 == 0 {
+			k, v, ok := sl.PopMin()
+			if !ok || k != alive[lo] || v != skiplist.V(k) {
+				t.Fatalf("PopMin() = (%d, %f, %v), want (%d, %d, true)", k, v, ok, alive[lo], alive[lo])
+			}
+			lo++
+			if nd := first(); nd != nil && nd.key <= k {
+				t.Fatalf("after PopMin() = %d the first linked node is %d", k, nd.key)
+			}
+		} else {
+			k, v, ok := sl.PopMax()
+			if !ok || k != alive[hi] || v != skiplist.V(k) {
+				t.Fatalf("PopMax() = (%d, %f, %v), want (%d, %d, true)", k, v, ok, alive[hi], alive[hi])
+			}
+			hi--
+			if nd := sl.findLast(); nd != sl.head && nd.key >= k {
+				t.Fatalf("after PopMax() = %d the last linked node is %d", k, nd.key)
+			}
+		}
+	}
+	if _, _, ok := sl.PopMin(); ok {
+		t.Error("PopMin() on drained list ok = true, want false")
+	}
+	if nd := first(); sl.Len() != 0 || nd != nil {
+		t.Errorf("drained list has Len() = %d and first node %v, want 0 and nil", sl.Len(), nd)
+	}
+	if !analyTool.CheckStruct(sl) {
+		t.Error("CheckStruct() = false after draining")
+	}
+}
+
+func TestConcurrentSplayListInterface(t *testing.T) {
+	var _ skiplist.SkipList = (*ConcurrentSplayList[skiplist.K, skiplist.V])(nil)
+	var _ skiplist.Analyable = (*ConcurrentSplayList[skiplist.K, skiplist.V])(nil)
+	var _ skiplist.Nodelike = (*cNode[skiplist.K, skiplist.V])(nil)
+}
+
+// p = 1 時每次存取都會進入 update，單執行緒下結構應與 SplayList 完全相同
+func TestConcurrentSplayListConformance(t *testing.T) {
+	skiplisttest.Run(t, func() skiplist.SkipList { return NewConcurrentSplayList(0.5) })
+}
+
+func TestConcurrentSplayListMatchesSequential(t *testing.T) {
+	seq := NewSplayList(1)
+	con := NewConcurrentSplayList(1)
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 20000; i++ {
+		key := skiplist.K(r.ExpFloat64() * 50)
+		switch r.Intn(8) {
+		case 0:
+			seq.Delete(key)
+			con.Delete(key)
+		case 1, 2:
+			seq.Put(key, skiplist.V(i))
+			con.Put(key, skiplist.V(i))
+		default:
+			seq.Get(key)
+			con.Get(key)
+		}
+	}
+
+	if con.Len() != seq.Len() {
+		t.Fatalf("Len() = %d, want %d", con.Len(), seq.Len())
+	}
+	_, seqLevel := seq.GetMaxStats()
+	_, conLevel := con.GetMaxStats()
+	if conLevel != seqLevel {
+		t.Errorf("GetMaxStats() level = %d, want %d", conLevel, seqLevel)
+	}
+	for key := skiplist.K(0); key < 400; key++ {
+		seqStep, _ := analyTool.FindStep(seq, key)
+		conStep, _ := analyTool.FindStep(con, key)
+		if seqStep != conStep {
+			t.Fatalf("FindStep(%d) = %d, want %d as in SplayList", key, conStep, seqStep)
+		}
+	}
+}
+
+func TestConcurrentSplayListConcurrent(t *testing.T) {
+	list := NewConcurrentSplayList(0.3)
+	const goroutines = 16
+	const opsPerGoroutine = 5000
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(id)))
+			for i := 0; i < opsPerGoroutine; i++ {
+				key := skiplist.K(r.ExpFloat64() * 100)
+				switch r.Intn(8) {
+				case 0:
+					list.Delete(key)
+				case 1:
+					list.Put(key, skiplist.V(key))
+				case 2:
+					list.Ceiling(key)
+				default:
+					if v, found := list.Get(key); found && v != skiplist.V(key) {
+						t.Errorf("Get(%d) = %f, want %d", key, v, key)
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	prev := skiplist.K(-1)
+	n := 0
+	for k := range list.All() {
+		if k <= prev {
+			t.Fatalf("All() yielded %d after %d", k, prev)
+		}
+		prev = k
+		n++
+	}
+	if n != list.Len() {
+		t.Errorf("All() yielded %d keys, Len() = %d", n, list.Len())
+	}
+}
+
+// 每個 goroutine 只操作自己的 key，結束後內容必須完全正確，用來確認讀取不會因結構調整而漏掉節點
+func TestConcurrentSplayListDisjointKeys(t *testing.T) {
+	list := NewConcurrentSplayList(1)
+	const goroutines = 8
+	const keysPerGoroutine = 500
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for i := 0; i < keysPerGoroutine; i++ {
+				key := skiplist.K(i*goroutines + id)
+				list.Put(key, skiplist.V(key))
+				for j := 0; j <= i; j += 7 {
+					k := skiplist.K(j*goroutines + id)
+					if v, found := list.Get(k); !found || v != skiplist.V(k) {
+						t.Errorf("Get(%d) = (%f, %v) while inserting, want (%d, true)", k, v, found, k)
+						return
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if list.Len() != goroutines*keysPerGoroutine {
+		t.Errorf("Len() = %d, want %d", list.Len(), goroutines*keysPerGoroutine)
+	}
+}
+
+// cPhysicalLen 回傳 zero level 實際串接的節點數（含墓碑）
+func cPhysicalLen(list *ConcurrentSplayList[skiplist.K, skiplist.V]) int {
+	n := 0
+	for nd := list.head.next[list.zeroLevel.Load()].Load(); nd != nil; nd = nd.next[list.zeroLevel.Load()].Load() {
+		n++
+	}
+	return n
+}
+
+func TestConcurrentSplayListPopDrain(t *testing.T) {
+	list := NewConcurrentSplayList(0.5)
+	alive := drainKeys(list, 30000)
+	lo, hi := 0, len(alive)-1
+	for i := 0; lo <= hi; i++ {
+		if i%2 == 0 {
+			k, v, ok := list.PopMin()
+			if !ok || k != alive[lo] || v != skiplist.V(k) {
+				t.Fatalf("PopMin() = (%d, %f, %v), want (%d, %d, true)", k, v, ok, alive[lo], alive[lo])
+			}
+			lo++
+			if nd := list.head.next[list.zeroLevel.Load()].Load(); nd != nil && nd.key <= k {
+				t.Fatalf("after PopMin() = %d the first linked node is %d", k, nd.key)
+			}
+		} else {
+			k, v, ok := list.PopMax()
+			if !ok || k != alive[hi] || v != skiplist.V(k) {
+				t.Fatalf("PopMax() = (%d, %f, %v), want (%d, %d, true)", k, v, ok, alive[hi], alive[hi])
+			}
+			hi--
+			if nd := list.findLast(); nd != list.head && nd.key >= k {
+				t.Fatalf("after PopMax() = %d the last linked node is %d", k, nd.key)
+			}
+		}
+	}
+	if n := cPhysicalLen(list); list.Len() != 0 || n != 0 {
+		t.Errorf("drained list has Len() = %d and %d linked nodes, want 0 and 0", list.Len(), n)
+	}
+}
+
+// 插入、讀取與 PopMin/PopMax 同時進行，每個 key 只能被取出一次，
+// 結束後剩下的 key 與取出的 key 合起來恰好是所有插入的 key
+func TestConcurrentSplayListPopWhilePut(t *testing.T) {
+	list := NewConcurrentSplayList(0.5)
+	const producers, consumers, perProducer = 4, 4, 3000
+	var mu sync.Mutex
+	seen := make(map[skiplist.K]bool, producers*perProducer)
+	var producing, consuming sync.WaitGroup
+	done := make(chan struct{})
+	for g := 0; g < producers; g++ {
+		producing.Add(1)
+		go func(id int) {
+			defer producing.Done()
+			for i := 0; i < perProducer; i++ {
+				key := skiplist.K(i*producers + id)
+				list.Put(key, 1)
+				list.Get(key / 2)
+			}
+		}(g)
+	}
+	for g := 0; g < consumers; g++ {
+		consuming.Add(1)
+		go func(id int) {
+			defer consuming.Done()
+			pop := list.PopMin
+			if id%2 == 1 {
+				pop = list.PopMax
+			}
+			for {
+				k, _, ok := pop()
+				if !ok {
+					select {
+					case <-done:
+						return
+					default:
+						continue
+					}
+				}
+				mu.Lock()
+				if seen[k] {
+					t.Errorf("key %d popped twice", k)
+				}
+				seen[k] = true
+				mu.Unlock()
+			}
+		}(g)
+	}
+	producing.Wait()
+	close(done)
+	consuming.Wait()
+
+	for k := range list.All() {
+		if seen[k] {
+			t.Errorf("key %d both popped and still in the list", k)
+		}
+		seen[k] = true
+	}
+	if len(seen) != producers*perProducer {
+		t.Errorf("popped and remaining keys = %d, want %d", len(seen), producers*perProducer)
+	}
+	if n := cPhysicalLen(list); n != list.Len() {
+		t.Errorf("%d linked nodes for Len() = %d, want no leftover removed nodes", n, list.Len())
+	}
+}
+
+// BenchmarkConcurrentSplayList 比較不同 goroutine 數下的吞吐量，
+// locked 為以 syncsl 包裝的 SplayList，作為全域鎖的對照組
+func BenchmarkConcurrentSplayList(b *testing.B) {
+	const keyRange = 1 << 14
+	r := rand.New(rand.NewSource(1))
+	zipf := rand.NewZipf(r, 1.1, 1, keyRange-1)
+	keys := make([]skiplist.K, 1<<16)
+	for i := range keys {
+		keys[i] = skiplist.K(zipf.Uint64())
+	}
+
+	impls := []struct {
+		name string
+		new  func() skiplist.SkipList
+	}{
+		{"concurrent", func() skiplist.SkipList { return NewConcurrentSplayList(0.1) }},
+		{"locked", func() skiplist.SkipList { return syncsl.NewSyncSkipList(NewSplayList(0.1), syncsl.ExclusiveRead) }},
+	}
+	for _, impl := range impls {
+		for _, threads := range []int{1, 2, 4, 8} {
+			b.Run(fmt.Sprintf("%s/goroutines=%d", impl.name, threads), func(b *testing.B) {
+				sl := impl.new()
+				// 依亂數順序預先填入一半的 key，避免遞增插入讓結構退化
+				for _, i := range r.Perm(keyRange / 2) {
+					sl.Put(skiplist.K(2*i), skiplist.V(2*i))
+				}
+				b.ResetTimer()
+				var wg sync.WaitGroup
+				for g := 0; g < threads; g++ {
+					wg.Add(1)
+					go func(id int) {
+						defer wg.Done()
+						for i := id; i < b.N; i += threads {
+							key := keys[i&(len(keys)-1)]
+							if i%10 == 0 {
+								sl.Put(key, skiplist.V(key))
+							} else {
+								sl.Get(key)
+							}
+						}
+					}(g)
+				}
+				wg.Wait()
+				b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "ops/s")
+			})
+		}
+	}
+}
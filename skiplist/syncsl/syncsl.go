@@ -0,0 +1,227 @@
+package syncsl
+
+import (
+	"iter"
+	"sync"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+)
+
+// Mode 決定讀取操作取得的鎖
+type Mode int
+
+const (
+	// SharedRead 讀取取得讀鎖，適用於查詢不改變結構的實作，如 basic 與 la
+	SharedRead Mode = iota
+	// ExclusiveRead 讀取也取得寫鎖，適用於查詢時會調整結構的實作，
+	// 如 splay、Tlist、rebuildsl、gravity 與 falldown
+	ExclusiveRead
+)
+
+// SyncSkipList 以 RWMutex 保護任意 skip list，可安全地被多個 goroutine 同時使用
+type SyncSkipList[K, V any] struct {
+	mu   sync.RWMutex
+	sl   skiplist.SkipListOf[K, V]
+	mode Mode
+}
+
+// NewSyncSkipList 包裝 sl，之後只應透過回傳的 SyncSkipList 存取 sl
+func NewSyncSkipList[K, V any](sl skiplist.SkipListOf[K, V], mode Mode) *SyncSkipList[K, V] {
+	return &SyncSkipList[K, V]{sl: sl, mode: mode}
+}
+
+func (s *SyncSkipList[K, V]) readLock() {
+	if s.mode == ExclusiveRead {
+		s.mu.Lock()
+	} else {
+		s.mu.RLock()
+	}
+}
+
+func (s *SyncSkipList[K, V]) readUnlock() {
+	if s.mode == ExclusiveRead {
+		s.mu.Unlock()
+	} else {
+		s.mu.RUnlock()
+	}
+}
+
+func (s *SyncSkipList[K, V]) Contains(key K) bool {
+	s.readLock()
+	defer s.readUnlock()
+	return s.sl.Contains(key)
+}
+
+func (s *SyncSkipList[K, V]) Get(key K) (V, bool) {
+	s.readLock()
+	defer s.readUnlock()
+	return s.sl.Get(key)
+}
+
+func (s *SyncSkipList[K, V]) Put(key K, value V) (V, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sl.Put(key, value)
+}
+
+func (s *SyncSkipList[K, V]) Delete(key K) (V, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sl.Delete(key)
+}
+
+func (s *SyncSkipList[K, V]) Len() int {
+	s.readLock()
+	defer s.readUnlock()
+	return s.sl.Len()
+}
+
+// GetHead 回傳內部的頭節點，走訪節點時不受鎖保護，僅供單執行緒的分析工具使用
+func (s *SyncSkipList[K, V]) GetHead() skiplist.NodelikeOf[K, V] {
+	s.readLock()
+	defer s.readUnlock()
+	return s.sl.GetHead()
+}
+
+// Iterator 回傳每次移動都會取得讀鎖的迭代器。
+// 走訪期間其他 goroutine 仍可修改串列，結果只保證依序且不重複。
+func (s *SyncSkipList[K, V]) Iterator() skiplist.IteratorOf[K, V] {
+	s.readLock()
+	defer s.readUnlock()
+	return &syncIterator[K, V]{s: s, it: s.sl.Iterator()}
+}
+
+// Range 在整個走訪期間持有讀鎖，fn 內不可再呼叫同一個 SyncSkipList 的方法
+func (s *SyncSkipList[K, V]) Range(lo, hi K, fn func(key K, value V) bool) {
+	s.readLock()
+	defer s.readUnlock()
+	s.sl.Range(lo, hi, fn)
+}
+
+// All 以 Iterator 逐步走訪，迴圈內可以呼叫同一個 SyncSkipList 的方法
+func (s *SyncSkipList[K, V]) All() iter.Seq2[K, V] {
+	return skiplist.AllOf(s.Iterator)
+}
+
+// Backward 以 Iterator 逐步反向走訪，迴圈內可以呼叫同一個 SyncSkipList 的方法
+func (s *SyncSkipList[K, V]) Backward() iter.Seq2[K, V] {
+	return skiplist.BackwardOf(s.Iterator)
+}
+
+func (s *SyncSkipList[K, V]) Floor(key K) (K, V, bool) {
+	s.readLock()
+	defer s.readUnlock()
+	return s.sl.Floor(key)
+}
+
+func (s *SyncSkipList[K, V]) Ceiling(key K) (K, V, bool) {
+	s.readLock()
+	defer s.readUnlock()
+	return s.sl.Ceiling(key)
+}
+
+func (s *SyncSkipList[K, V]) Predecessor(key K) (K, V, bool) {
+	s.readLock()
+	defer s.readUnlock()
+	return s.sl.Predecessor(key)
+}
+
+func (s *SyncSkipList[K, V]) Successor(key K) (K, V, bool) {
+	s.readLock()
+	defer s.readUnlock()
+	return s.sl.Successor(key)
+}
+
+func (s *SyncSkipList[K, V]) Min() (K, V, bool) {
+	s.readLock()
+	defer s.readUnlock()
+	return s.sl.Min()
+}
+
+func (s *SyncSkipList[K, V]) Max() (K, V, bool) {
+	s.readLock()
+	defer s.readUnlock()
+	return s.sl.Max()
+}
+
+// PopMin 在同一個寫鎖內取得並刪除最小元素，多個消費者不會取得同一個元素
+func (s *SyncSkipList[K, V]) PopMin() (K, V, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sl.PopMin()
+}
+
+// PopMax 在同一個寫鎖內取得並刪除最大元素
+func (s *SyncSkipList[K, V]) PopMax() (K, V, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sl.PopMax()
+}
+
+// Update 在寫鎖內以被包裝的 skip list 呼叫 fn，供呼叫實作特有的方法（如 la 的 PutWithNP），
+// fn 內不可再呼叫同一個 SyncSkipList 的方法
+func (s *SyncSkipList[K, V]) Update(fn func(sl skiplist.SkipListOf[K, V])) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.sl)
+}
+
+// syncIterator 每個操作都在鎖內轉交給底層迭代器
+type syncIterator[K, V any] struct {
+	s  *SyncSkipList[K, V]
+	it skiplist.IteratorOf[K, V]
+}
+
+func (it *syncIterator[K, V]) Valid() bool {
+	it.s.readLock()
+	defer it.s.readUnlock()
+	return it.it.Valid()
+}
+
+func (it *syncIterator[K, V]) Key() K {
+	it.s.readLock()
+	defer it.s.readUnlock()
+	return it.it.Key()
+}
+
+func (it *syncIterator[K, V]) Value() V {
+	it.s.readLock()
+	defer it.s.readUnlock()
+	return it.it.Value()
+}
+
+func (it *syncIterator[K, V]) Next() {
+	it.s.readLock()
+	defer it.s.readUnlock()
+	it.it.Next()
+}
+
+func (it *syncIterator[K, V]) Prev() {
+	it.s.readLock()
+	defer it.s.readUnlock()
+	it.it.Prev()
+}
+
+func (it *syncIterator[K, V]) Seek(key K) {
+	it.s.readLock()
+	defer it.s.readUnlock()
+	it.it.Seek(key)
+}
+
+func (it *syncIterator[K, V]) SeekBefore(key K) {
+	it.s.readLock()
+	defer it.s.readUnlock()
+	it.it.SeekBefore(key)
+}
+
+func (it *syncIterator[K, V]) SeekToFirst() {
+	it.s.readLock()
+	defer it.s.readUnlock()
+	it.it.SeekToFirst()
+}
+
+func (it *syncIterator[K, V]) SeekToLast() {
+	it.s.readLock()
+	defer it.s.readUnlock()
+	it.it.SeekToLast()
+}
@@ -0,0 +1,141 @@
+package syncsl_test
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/Hakuto4838/SkipList.git/skiplist"
+	tlist "github.com/Hakuto4838/SkipList.git/skiplist/Tlist"
+	"github.com/Hakuto4838/SkipList.git/skiplist/basic"
+	"github.com/Hakuto4838/SkipList.git/skiplist/la"
+	"github.com/Hakuto4838/SkipList.git/skiplist/splay"
+	"github.com/Hakuto4838/SkipList.git/skiplist/syncsl"
+)
+
+func TestSyncSkipListInterface(t *testing.T) {
+	var _ skiplist.SkipList = (*syncsl.SyncSkipList[skiplist.K, skiplist.V])(nil)
+}
+
+// hammer 讓多個 goroutine 同時讀寫，需搭配 go test -race 才能發現資料競爭
+func hammer(t *testing.T, s *syncsl.SyncSkipList[skiplist.K, skiplist.V]) {
+	const goroutines = 16
+	const opsPerGoroutine = 2000
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(id)))
+			for i := 0; i < opsPerGoroutine; i++ {
+				key := skiplist.K(r.Intn(200))
+				switch r.Intn(6) {
+				case 0:
+					s.Put(key, skiplist.V(key))
+				case 1:
+					s.Delete(key)
+				case 2:
+					if v, found := s.Get(key); found && v != skiplist.V(key) {
+						t.Errorf("Get(%d) = %f, want %d", key, v, key)
+					}
+				case 3:
+					s.Ceiling(key)
+				case 4:
+					s.Range(key, key+10, func(k skiplist.K, v skiplist.V) bool { return true })
+				default:
+					prev := skiplist.K(-1)
+					for k := range s.All() {
+						if k <= prev {
+							t.Errorf("All() yielded %d after %d", k, prev)
+						}
+						prev = k
+						if k > key {
+							break
+						}
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	n := 0
+	for range s.All() {
+		n++
+	}
+	if n != s.Len() {
+		t.Errorf("All() yielded %d keys, Len() = %d", n, s.Len())
+	}
+}
+
+func TestSyncSkipListSharedRead(t *testing.T) {
+	hammer(t, syncsl.NewSyncSkipList(basic.NewBasicSkipList(42), syncsl.SharedRead))
+	hammer(t, syncsl.NewSyncSkipList(la.NewLASkipList(42), syncsl.SharedRead))
+}
+
+func TestSyncSkipListExclusiveRead(t *testing.T) {
+	hammer(t, syncsl.NewSyncSkipList(splay.NewSplayList(0.5), syncsl.ExclusiveRead))
+	hammer(t, syncsl.NewSyncSkipList(tlist.NewSkipList(2), syncsl.ExclusiveRead))
+}
+
+func TestSyncSkipListPopMin(t *testing.T) {
+	const n = 1000
+	s := syncsl.NewSyncSkipList(basic.NewBasicSkipList(42), syncsl.SharedRead)
+	for i := 0; i < n; i++ {
+		s.Put(skiplist.K(i), skiplist.V(i))
+	}
+
+	var mu sync.Mutex
+	seen := make(map[skiplist.K]int)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				k, _, ok := s.PopMin()
+				if !ok {
+					return
+				}
+				mu.Lock()
+				seen[k]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != n {
+		t.Errorf("popped %d distinct keys, want %d", len(seen), n)
+	}
+	for k, c := range seen {
+		if c != 1 {
+			t.Errorf("key %d popped %d times, want once", k, c)
+		}
+	}
+}
+
+func TestSyncSkipListUpdate(t *testing.T) {
+	s := syncsl.NewSyncSkipList(la.NewLASkipList(42), syncsl.SharedRead)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				key := skiplist.K(g*100 + i)
+				s.Update(func(sl skiplist.SkipList) {
+					sl.(*la.LASkipList[skiplist.K, skiplist.V]).PutWithNP(key, skiplist.V(key), 1)
+				})
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if s.Len() != 800 {
+		t.Errorf("Len() = %d, want 800", s.Len())
+	}
+	if v, ok := s.Get(123); !ok || v != 123 {
+		t.Errorf("Get(123) = %v, %v, want 123, true", v, ok)
+	}
+}